@@ -0,0 +1,145 @@
+package syno
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned by Do for errors reported by the API. It identifies the
+// request that failed, and unwraps to the Error code, or the ResponseError if
+// the response included details, so errors.Is and errors.As work as usual.
+type APIError struct {
+	API     string
+	Method  string
+	Version string
+	Code    Error
+	Err     error
+}
+
+func (e *APIError) Error() string {
+	msg := ErrorString(e.API, e.Code)
+	if re, ok := e.Err.(*ResponseError); ok && len(re.Details) > 0 {
+		msg = re.message(e.API)
+	}
+	var ctx []string
+	for _, s := range []string{e.API, e.Method} {
+		if s != "" {
+			ctx = append(ctx, s)
+		}
+	}
+	if e.Version != "" {
+		ctx = append(ctx, "v"+e.Version)
+	}
+	if len(ctx) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(
+		"syno: %s: %s",
+		strings.Join(ctx, " "),
+		strings.TrimPrefix(msg, "syno: "),
+	)
+}
+
+// Unwrap returns the underlying Error code or ResponseError.
+func (e *APIError) Unwrap() error { return e.Err }
+
+// wrapAPIError adds the request context to errors reported by the API.
+func wrapAPIError(r *Request, err error) error {
+	var code Error
+	switch e := err.(type) {
+	case Error:
+		code = e
+	case *ResponseError:
+		code = e.Code
+		e.API = r.API
+	default:
+		return err
+	}
+	return &APIError{
+		API:     r.API,
+		Method:  r.Method,
+		Version: r.Version,
+		Code:    code,
+		Err:     err,
+	}
+}
+
+// maxHTTPErrorBody is the number of bytes of the response body kept in an
+// HTTPError.
+const maxHTTPErrorBody = 512
+
+// HTTPError is returned by Do for responses with a status other than 2xx,
+// such as a 404 page or a 502 from a reverse proxy in front of DSM. Body holds
+// the start of the response body to help identify where it came from.
+type HTTPError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	body := strings.TrimSpace(string(e.Body))
+	if body == "" {
+		return fmt.Sprintf("syno: HTTP status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("syno: HTTP status %d: %s", e.StatusCode, body)
+}
+
+// newHTTPError returns an HTTPError for the response if its status is not
+// successful, reading the start of the body.
+func newHTTPError(hres *http.Response) error {
+	if hres.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(hres.Body, maxHTTPErrorBody))
+	return &HTTPError{StatusCode: hres.StatusCode, Body: body}
+}
+
+// maxDecodeErrorBody is the number of bytes of the response body kept in a
+// DecodeError.
+const maxDecodeErrorBody = 512
+
+// DecodeError is returned by Do when the response could not be decoded, such
+// as when a reverse proxy or login redirect returns HTML instead of JSON. URL
+// and Body identify the response with credentials redacted, and Body only
+// holds the start of the response body.
+type DecodeError struct {
+	URL  string
+	Body []byte
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("syno: decoding response from %s: %v: %q", e.URL, e.Err, e.Body)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+func newDecodeError(hreq *http.Request, body []byte, err error) error {
+	if len(body) > maxDecodeErrorBody {
+		body = body[:maxDecodeErrorBody]
+	}
+	u := *hreq.URL
+	u.RawQuery = redactQuery(u.RawQuery)
+	return &DecodeError{URL: u.String(), Body: redactBody(body), Err: err}
+}
+
+// prefixReader keeps the start of what is read through it, for use in a
+// DecodeError.
+type prefixReader struct {
+	r      io.Reader
+	prefix []byte
+}
+
+func (p *prefixReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if room := maxDecodeErrorBody - len(p.prefix); room > 0 {
+		if room > n {
+			room = n
+		}
+		p.prefix = append(p.prefix, b[:room]...)
+	}
+	return n, err
+}
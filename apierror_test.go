@@ -0,0 +1,161 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestAPIErrorString(t *testing.T) {
+	cases := []struct {
+		Err    *APIError
+		String string
+	}{
+		{
+			Err: &APIError{
+				API:     authLoginAPI,
+				Method:  "login",
+				Version: "3",
+				Code:    ErrorAuthNoSuchAccount,
+				Err:     ErrorAuthNoSuchAccount,
+			},
+			String: "syno: SYNO.API.Auth login v3: no such account or incorrect password (400)",
+		},
+		{
+			Err:    &APIError{Code: ErrorUnknown, Err: ErrorUnknown},
+			String: "syno: unknown API error (100)",
+		},
+		{
+			Err: &APIError{
+				API:  "SYNO.FileStation.Delete",
+				Code: 900,
+				Err: &ResponseError{
+					Code:    900,
+					Details: []ErrorDetail{{Code: 408, Path: "/a"}},
+				},
+			},
+			String: "syno: SYNO.FileStation.Delete: failed to delete files or folders (900): " +
+				"/a: no such file or directory (408)",
+		},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, c.Err.Error(), c.String)
+	}
+}
+
+func TestClientResponseErrorStrings(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": false,
+					"error": {"code": 900, "errors": [{"code": 408, "path": "/a"}]}
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{
+		API:     "SYNO.FileStation.Delete",
+		Method:  "delete",
+		Version: "2",
+	}, nil)
+	ensure.DeepEqual(t, err.Error(), "syno: SYNO.FileStation.Delete delete v2: "+
+		"failed to delete files or folders (900): /a: no such file or directory (408)")
+	var re *ResponseError
+	ensure.True(t, errors.As(err, &re))
+	ensure.DeepEqual(t, re.Error(),
+		"syno: failed to delete files or folders (900): /a: no such file or directory (408)")
+}
+
+func TestClientAPIErrorContext(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":false,"error":{"code":101}}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Call(context.Background(), DownloadTaskList{}, nil)
+	ensure.True(t, errors.Is(err, ErrorInvalidParameter))
+	var apiErr *APIError
+	ensure.True(t, errors.As(err, &apiErr))
+	ensure.DeepEqual(t, apiErr, &APIError{
+		API:     downloadTaskAPI,
+		Method:  "list",
+		Version: downloadTaskVersion,
+		Code:    ErrorInvalidParameter,
+		Err:     ErrorInvalidParameter,
+	})
+}
+
+func TestWrapAPIErrorOther(t *testing.T) {
+	givenErr := errors.New("")
+	ensure.DeepEqual(t, wrapAPIError(&Request{}, givenErr), givenErr)
+	ensure.Nil(t, wrapAPIError(&Request{}, nil))
+}
+
+func TestClientHTTPError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body: ioutil.NopCloser(strings.NewReader(
+					"<html>not found</html>\n")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	var httpErr *HTTPError
+	ensure.True(t, errors.As(err, &httpErr))
+	ensure.DeepEqual(t, httpErr.StatusCode, http.StatusNotFound)
+	ensure.DeepEqual(t, err.Error(), "syno: HTTP status 404: <html>not found</html>")
+}
+
+func TestHTTPErrorBodyLimit(t *testing.T) {
+	err := newHTTPError(&http.Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       ioutil.NopCloser(strings.NewReader(strings.Repeat("a", 1024))),
+	})
+	ensure.DeepEqual(t, len(err.(*HTTPError).Body), maxHTTPErrorBody)
+	ensure.Nil(t, newHTTPError(&http.Response{StatusCode: http.StatusOK}))
+}
+
+func TestClientDecodeError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("<html>login</html>")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{API: "SYNO.Foo", SID: "secret"}, nil)
+	var decodeErr *DecodeError
+	ensure.True(t, errors.As(err, &decodeErr))
+	ensure.DeepEqual(t, string(decodeErr.Body), "<html>login</html>")
+	ensure.StringContains(t, decodeErr.URL, "_sid=REDACTED")
+	ensure.StringDoesNotContain(t, err.Error(), "secret")
+	ensure.StringContains(t, err.Error(), "invalid character '<'")
+}
+
+func TestPrefixReader(t *testing.T) {
+	r := &prefixReader{r: strings.NewReader(strings.Repeat("a", 1024))}
+	b, err := ioutil.ReadAll(r)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(b), 1024)
+	ensure.DeepEqual(t, len(r.prefix), maxDecodeErrorBody)
+}
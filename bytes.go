@@ -0,0 +1,30 @@
+package syno
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Bytes is a size or bandwidth in bytes. Depending on the API version sizes
+// are returned as numbers or quoted strings, both of which are accepted.
+type Bytes int64
+
+// UnmarshalJSON decodes a number of bytes given as a number or a string.
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*b = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		var f float64
+		if err := json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		n = int64(f)
+	}
+	*b = Bytes(n)
+	return nil
+}
@@ -0,0 +1,45 @@
+package syno
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestBytesUnmarshal(t *testing.T) {
+	cases := []struct {
+		JSON  string
+		Bytes Bytes
+	}{
+		{JSON: `42`, Bytes: 42},
+		{JSON: `"42"`, Bytes: 42},
+		{JSON: `1e3`, Bytes: 1000},
+		{JSON: `""`, Bytes: 0},
+		{JSON: `null`, Bytes: 0},
+	}
+	for _, c := range cases {
+		var v Bytes
+		ensure.Nil(t, json.Unmarshal([]byte(c.JSON), &v), c.JSON)
+		ensure.DeepEqual(t, v, c.Bytes, c.JSON)
+	}
+}
+
+func TestBytesUnmarshalError(t *testing.T) {
+	var v Bytes
+	ensure.NotNil(t, json.Unmarshal([]byte(`"big"`), &v))
+}
+
+func TestDownloadTaskTransferUnmarshal(t *testing.T) {
+	var task DownloadTask
+	err := json.Unmarshal([]byte(`{
+		"size": "1024",
+		"additional": {"transfer": {"size_downloaded": 512, "speed_download": "64"}}
+	}`), &task)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, task.Size, Bytes(1024))
+	ensure.DeepEqual(t, task.Additional.Transfer, &DownloadTaskTransfer{
+		SizeDownloaded: 512,
+		SpeedDownload:  64,
+	})
+}
@@ -0,0 +1,104 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Cache stores the data of cacheable responses, as configured with
+// ClientCache. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached data for the key, if present and not expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores the data for the key, to expire after the given duration.
+	Set(key string, data []byte, ttl time.Duration)
+}
+
+type memoryCacheEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// MemoryCache is a Cache holding the responses in memory. The zero value is
+// ready to use.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+// Get returns the cached data for the key, if present and not expired.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.data, true
+}
+
+// Set stores the data for the key, to expire after the given duration.
+func (m *MemoryCache) Set(key string, data []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]memoryCacheEntry)
+	}
+	m.entries[key] = memoryCacheEntry{data: data, expires: time.Now().Add(ttl)}
+}
+
+// cacheKey identifies the response of a request made with the sid it is sent
+// with, so responses are never shared between accounts.
+func cacheKey(r *Request, sid string) string {
+	return encodeQuery(r, sid, "") + "#" + r.Session + "#" + r.Path
+}
+
+// doCached serves the request from the cache if possible, and otherwise
+// stores the data of a successful response in it. Requests whose response is
+// not decoded as usual are never cached.
+func (c *Client) doCached(ctx context.Context, r *Request, data interface{}) error {
+	switch data.(type) {
+	case *Response, *Stream, dataDecoder:
+		return c.send(ctx, r, data)
+	}
+	sid, err := c.requestSID(ctx, r)
+	if err != nil {
+		return err
+	}
+	key := cacheKey(r, sid)
+	if b, ok := c.cache.Get(key); ok {
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(b, data)
+	}
+	var raw json.RawMessage
+	if err := c.send(ctx, r, &raw); err != nil {
+		return err
+	}
+	c.cache.Set(key, raw, c.cacheTTL)
+	if data == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, data)
+}
+
+// ClientCache configures the Client to cache the responses of requests marked
+// Cacheable in the given store for the duration of the ttl, such as APIInfo,
+// FileStationInfo, FileStationListShare and DownloadStationGetInfo. The cache
+// is keyed on the API, version, method and parameters along with the sid the
+// request is sent with, so Clients made with WithSID can share a store.
+func ClientCache(store Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.cache = store
+		c.cacheTTL = ttl
+		return nil
+	}
+}
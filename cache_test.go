@@ -0,0 +1,119 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func TestMemoryCache(t *testing.T) {
+	var m MemoryCache
+	_, ok := m.Get("a")
+	ensure.False(t, ok)
+	m.Set("a", []byte("1"), time.Minute)
+	b, ok := m.Get("a")
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, string(b), "1")
+	m.Set("b", []byte("2"), -time.Second)
+	_, ok = m.Get("b")
+	ensure.False(t, ok)
+	ensure.DeepEqual(t, len(m.entries), 1)
+}
+
+func cacheClient(t *testing.T, requests *int) *Client {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientCache(&MemoryCache{}, time.Minute),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			*requests++
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    map[string]string{"query": v.Get("query")},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+func TestClientCache(t *testing.T) {
+	var requests int
+	c := cacheClient(t, &requests)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		var res map[string]string
+		ensure.Nil(t, c.Call(ctx, APIInfo{Query: []string{"a"}}, &res))
+		ensure.DeepEqual(t, res, map[string]string{"query": "a"})
+	}
+	ensure.Nil(t, c.Call(ctx, APIInfo{Query: []string{"a"}}, nil))
+	ensure.DeepEqual(t, requests, 1)
+
+	var res map[string]string
+	ensure.Nil(t, c.Call(ctx, APIInfo{Query: []string{"b"}}, &res))
+	ensure.DeepEqual(t, res, map[string]string{"query": "b"})
+	ensure.DeepEqual(t, requests, 2)
+}
+
+func TestClientCacheSkipped(t *testing.T) {
+	var requests int
+	c := cacheClient(t, &requests)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		ensure.Nil(t, c.Do(ctx, &Request{API: "SYNO.Foo"}, nil))
+		_, err := c.DoRaw(ctx, &Request{API: apiInfoAPI, Cacheable: true})
+		ensure.Nil(t, err)
+	}
+	ensure.DeepEqual(t, requests, 4)
+}
+
+func TestClientCacheError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientCache(&MemoryCache{}, time.Minute),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"error": map[string]int{"code": 105},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Call(context.Background(), APIInfo{}, nil)
+	ensure.True(t, errors.Is(err, ErrorPermissionDenied))
+}
+
+func TestClientCacheWithSID(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientCache(&MemoryCache{}, time.Minute),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    map[string]string{"sid": v.Get("_sid")},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ctx := context.Background()
+	for _, sid := range []string{"alice", "bob", "alice"} {
+		var res map[string]string
+		ensure.Nil(t, c.WithSID(sid).Call(ctx, FileStationListShare{}, &res))
+		ensure.DeepEqual(t, res, map[string]string{"sid": sid})
+	}
+}
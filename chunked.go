@@ -0,0 +1,101 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var errNoChunkRequest = errors.New("syno: chunked upload has no Chunk function")
+
+// UploadState tracks the progress of a ChunkedUpload. It can be persisted and
+// given back to a later ChunkedUpload to resume where the last one failed.
+type UploadState struct {
+	// Offset is the number of bytes that have been uploaded.
+	Offset int64 `json:"offset"`
+
+	// Chunks is the number of chunks that have been uploaded.
+	Chunks int `json:"chunks"`
+}
+
+// ChunkedUpload uploads a large file as a sequence of requests, each carrying
+// a single chunk. Chunks are read from Body using their offset, so a failed
+// chunk can be sent again and an interrupted upload resumed from its State.
+type ChunkedUpload struct {
+	// Body provides the file contents, which are Size bytes long.
+	Body io.ReaderAt
+	Size int64
+
+	// ChunkSize is the size of each chunk. If zero, 8MiB is used.
+	ChunkSize int64
+
+	// Chunk returns the request uploading the chunk read from body, found at
+	// the given offset in the file. The first chunk typically creates or
+	// overwrites the file, and later ones append to it, using whichever modes
+	// the API supports.
+	Chunk func(body io.Reader, offset int64) MarshalRequest
+
+	// Retries is the number of times a failed chunk is retried before the
+	// upload fails.
+	Retries int
+
+	// State is updated as chunks are uploaded. Set it to resume an upload.
+	State UploadState
+
+	// OnChunk, if set, is called with the State after every uploaded chunk,
+	// which allows for persisting it.
+	OnChunk func(UploadState)
+
+	// Progress, if set, is called as the file is uploaded.
+	Progress ProgressFunc
+}
+
+func (u *ChunkedUpload) chunkSize() int64 {
+	if u.ChunkSize <= 0 {
+		return 8 << 20
+	}
+	return u.ChunkSize
+}
+
+// UploadChunked sends the ChunkedUpload starting from its State. On failure,
+// the State reflects the chunks that were uploaded, and calling UploadChunked
+// again resumes the upload.
+func (c *Client) UploadChunked(ctx context.Context, u *ChunkedUpload) error {
+	if u.Chunk == nil {
+		return errNoChunkRequest
+	}
+	size := u.chunkSize()
+	for u.State.Offset < u.Size || (u.Size == 0 && u.State.Chunks == 0) {
+		n := u.Size - u.State.Offset
+		if n > size {
+			n = size
+		}
+		var err error
+		for attempt := 0; attempt <= u.Retries; attempt++ {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+			body := io.Reader(io.NewSectionReader(u.Body, u.State.Offset, n))
+			if u.Progress != nil {
+				done := u.State.Offset
+				body = ProgressReader(body, n, func(chunkDone, _ int64) {
+					u.Progress(done+chunkDone, u.Size)
+				})
+			}
+			err = c.Call(ctx, u.Chunk(body, u.State.Offset), nil)
+			var ve *ValidationError
+			if err == nil || errors.As(err, &ve) {
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+		u.State.Offset += n
+		u.State.Chunks++
+		if u.OnChunk != nil {
+			u.OnChunk(u.State)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+type chunkRecorder struct {
+	chunks  []string
+	offsets []int64
+	fail    map[int]int
+}
+
+func (r *chunkRecorder) client(t *testing.T) *Client {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(hreq *http.Request) (*http.Response, error) {
+			ensure.Nil(t, hreq.ParseMultipartForm(1<<20))
+			f, err := hreq.MultipartForm.File["file"][0].Open()
+			ensure.Nil(t, err)
+			b, err := ioutil.ReadAll(f)
+			ensure.Nil(t, err)
+			i := len(r.chunks)
+			if r.fail[i] > 0 {
+				r.fail[i]--
+				return nil, errors.New("flaky")
+			}
+			r.chunks = append(r.chunks, string(b))
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+func (r *chunkRecorder) chunk(body io.Reader, offset int64) MarshalRequest {
+	r.offsets = append(r.offsets, offset)
+	return funcMarshalRequest(func() (*Request, error) {
+		return &Request{
+			Files: []RequestFile{{Name: "file", Filename: "f", Body: body}},
+		}, nil
+	})
+}
+
+func TestUploadChunked(t *testing.T) {
+	r := &chunkRecorder{}
+	var states []UploadState
+	var progress []int64
+	u := &ChunkedUpload{
+		Body:      strings.NewReader("abcdefg"),
+		Size:      7,
+		ChunkSize: 3,
+		Chunk:     r.chunk,
+		OnChunk:   func(s UploadState) { states = append(states, s) },
+		Progress:  func(done, total int64) { progress = append(progress, done) },
+	}
+	ensure.Nil(t, r.client(t).UploadChunked(context.Background(), u))
+	ensure.DeepEqual(t, r.chunks, []string{"abc", "def", "g"})
+	ensure.DeepEqual(t, r.offsets, []int64{0, 3, 6})
+	ensure.DeepEqual(t, states, []UploadState{{3, 1}, {6, 2}, {7, 3}})
+	ensure.DeepEqual(t, progress, []int64{3, 6, 7})
+}
+
+func TestUploadChunkedResume(t *testing.T) {
+	r := &chunkRecorder{fail: map[int]int{1: 2}}
+	c := r.client(t)
+	u := &ChunkedUpload{
+		Body:      strings.NewReader("abcdefg"),
+		Size:      7,
+		ChunkSize: 3,
+		Chunk:     r.chunk,
+		Retries:   1,
+	}
+	ensure.NotNil(t, c.UploadChunked(context.Background(), u))
+	ensure.DeepEqual(t, u.State, UploadState{Offset: 3, Chunks: 1})
+	ensure.Nil(t, c.UploadChunked(context.Background(), u))
+	ensure.DeepEqual(t, r.chunks, []string{"abc", "def", "g"})
+	ensure.DeepEqual(t, u.State, UploadState{Offset: 7, Chunks: 3})
+}
+
+func TestUploadChunkedEmpty(t *testing.T) {
+	r := &chunkRecorder{}
+	u := &ChunkedUpload{Body: strings.NewReader(""), Chunk: r.chunk}
+	ensure.Nil(t, r.client(t).UploadChunked(context.Background(), u))
+	ensure.DeepEqual(t, r.chunks, []string{""})
+}
+
+func TestUploadChunkedErrors(t *testing.T) {
+	c := (&chunkRecorder{}).client(t)
+	err := c.UploadChunked(context.Background(), &ChunkedUpload{})
+	ensure.DeepEqual(t, err, errNoChunkRequest)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = c.UploadChunked(ctx, &ChunkedUpload{
+		Size:  1,
+		Chunk: func(io.Reader, int64) MarshalRequest { return nil },
+	})
+	ensure.DeepEqual(t, err, context.Canceled)
+}
@@ -0,0 +1,84 @@
+package syno
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+const redacted = "REDACTED"
+
+// redactedParams are the parameters whose values are never written to the
+// debug output.
+var redactedParams = []string{
+	"_sid",
+	"SynoToken",
+	"pass",
+	"passwd",
+	"password",
+	"unzip_password",
+	"otp_code",
+	"device_id",
+	"access_token",
+	"client_secret",
+}
+
+var redactedFields = regexp.MustCompile(`"(sid|synotoken|did)"(\s*):(\s*)"[^"]*"`)
+
+func redactQuery(q string) string {
+	v, err := url.ParseQuery(q)
+	if err != nil {
+		return redacted
+	}
+	for _, k := range redactedParams {
+		if _, ok := v[k]; ok {
+			v.Set(k, redacted)
+		}
+	}
+	return v.Encode()
+}
+
+func redactBody(b []byte) []byte {
+	return redactedFields.ReplaceAll(b, []byte(`"$1"$2:$3"`+redacted+`"`))
+}
+
+// debugRequest writes the request to the debug output, redacting credentials.
+func (c *Client) debugRequest(hreq *http.Request) {
+	u := *hreq.URL
+	u.RawQuery = redactQuery(u.RawQuery)
+	fmt.Fprintf(c.debug, "syno: %s %s\n", hreq.Method, &u)
+	if hreq.GetBody != nil {
+		if body, err := hreq.GetBody(); err == nil {
+			if b, err := ioutil.ReadAll(body); err == nil {
+				fmt.Fprintf(c.debug, "syno: request body: %s\n", redactQuery(string(b)))
+			}
+		}
+	}
+}
+
+// debugResponse writes the response to the debug output, redacting
+// credentials. The body is buffered and replaced so it can still be read.
+func (c *Client) debugResponse(hres *http.Response) error {
+	b, err := ioutil.ReadAll(hres.Body)
+	hres.Body.Close()
+	hres.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.debug, "syno: response %d: %s\n", hres.StatusCode, redactBody(b))
+	return nil
+}
+
+// ClientDebug configures the Client to write every request URL and the raw
+// response body to w. Credentials such as passwords, OTP codes and session
+// IDs are redacted.
+func ClientDebug(w io.Writer) ClientOption {
+	return func(c *Client) error {
+		c.debug = w
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+package syno
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestRedactQuery(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		redactQuery("account=a&passwd=p&otp_code=1&_sid=s&x=y"),
+		"_sid=REDACTED&account=a&otp_code=REDACTED&passwd=REDACTED&x=y",
+	)
+	ensure.DeepEqual(t, redactQuery("%zz"), redacted)
+}
+
+func TestRedactBody(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		string(redactBody([]byte(`{"data":{"sid": "s","synotoken":"t","x":"y"}}`))),
+		`{"data":{"sid": "REDACTED","synotoken":"REDACTED","x":"y"}}`,
+	)
+}
+
+func TestClientDebug(t *testing.T) {
+	var out bytes.Buffer
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("secret-sid"),
+		ClientDebug(&out),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":true,"data":{"sid":"new-sid"}}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	var res AuthLoginResponse
+	err = c.Do(context.Background(), &Request{
+		API:    authLoginAPI,
+		Method: "login",
+		Params: url.Values{"passwd": []string{"hunter2"}},
+	}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.SID, "new-sid")
+	ensure.DeepEqual(t, out.String(), "syno: GET http://foo.com/webapi/entry.cgi?"+
+		"_sid=REDACTED&api=SYNO.API.Auth&method=login&passwd=REDACTED&version=\n"+
+		`syno: response 200: {"success":true,"data":{"sid":"REDACTED"}}`+"\n")
+}
+
+func TestClientDebugPost(t *testing.T) {
+	var out bytes.Buffer
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientDebug(&out),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"success":true}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{
+		API:        "a",
+		HTTPMethod: "POST",
+		Params:     url.Values{"password": []string{"hunter2"}},
+	}, nil)
+	ensure.Nil(t, err)
+	ensure.True(t, strings.Contains(out.String(),
+		"syno: request body: api=a&method=&password=REDACTED&version=\n"))
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestClientDebugReadError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientDebug(ioutil.Discard),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{Body: ioutil.NopCloser(errReader{givenErr})}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Do(context.Background(), &Request{}, nil), givenErr)
+}
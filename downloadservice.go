@@ -0,0 +1,147 @@
+package syno
+
+import "context"
+
+// DownloadStation provides the common Download Station operations without
+// having to build the requests. Task operations use the SYNO.DownloadStation2
+// APIs when the server provides them, as CallDownloadTask does.
+type DownloadStation struct {
+	c *Client
+}
+
+// DownloadStation returns the Download Station operations for the Client.
+func (c *Client) DownloadStation() *DownloadStation {
+	return &DownloadStation{c: c}
+}
+
+// List lists the download tasks, including the given additional
+// information such as DownloadTaskAdditionalTransfer.
+func (d *DownloadStation) List(ctx context.Context, additional ...string) ([]DownloadTask, error) {
+	var res DownloadTaskListResponse
+	err := d.c.CallDownloadTask(ctx, DownloadTaskList{Additional: additional}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// Find lists the download tasks selected by the filter. The filter is sent to
+// the server when it provides the SYNO.DownloadStation2 APIs. Older servers,
+// and statuses the newer APIs cannot filter by, still list every task, so the
+// list is decoded as it is read and only the selected tasks are kept.
+func (d *DownloadStation) Find(
+	ctx context.Context,
+	f DownloadTaskFilter,
+	additional ...string,
+) ([]DownloadTask, error) {
+	r, err := d.c.downloadTaskRequest(ctx, DownloadTaskList{
+		Additional: additional,
+		Status:     f.Status,
+		Type:       f.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tasks []DownloadTask
+	_, err = CallEach(ctx, d.c, r, func(t DownloadTask) error {
+		if f.Match(t) {
+			tasks = append(tasks, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Get gets the download tasks with the given IDs.
+func (d *DownloadStation) Get(ctx context.Context, ids ...string) ([]DownloadTask, error) {
+	var res DownloadTaskGetInfoResponse
+	if err := d.c.Call(ctx, DownloadTaskGetInfo{IDs: ids}, &res); err != nil {
+		return nil, err
+	}
+	return res.Tasks, nil
+}
+
+// Create creates a download task for the URI, saved to the destination or
+// the default destination if empty.
+func (d *DownloadStation) Create(ctx context.Context, uri, destination string) error {
+	r := DownloadTaskCreate{URI: uri, Destination: destination}
+	return d.c.CallDownloadTask(ctx, r, nil)
+}
+
+// Delete deletes the download tasks with the given IDs.
+func (d *DownloadStation) Delete(ctx context.Context, ids ...string) (DownloadTaskResults, error) {
+	return d.c.CallDownloadTaskBulk(ctx, ids, func(ids []string) MarshalRequest {
+		return DownloadTaskDelete{IDs: ids}
+	})
+}
+
+// Pause pauses the download tasks with the given IDs.
+func (d *DownloadStation) Pause(ctx context.Context, ids ...string) (DownloadTaskResults, error) {
+	return d.c.CallDownloadTaskBulk(ctx, ids, func(ids []string) MarshalRequest {
+		return DownloadTaskPause{IDs: ids}
+	})
+}
+
+// Resume resumes the paused download tasks with the given IDs.
+func (d *DownloadStation) Resume(ctx context.Context, ids ...string) (DownloadTaskResults, error) {
+	return d.c.CallDownloadTaskBulk(ctx, ids, func(ids []string) MarshalRequest {
+		return DownloadTaskResume{IDs: ids}
+	})
+}
+
+// Stats gets the total transfer speeds of Download Station.
+func (d *DownloadStation) Stats(ctx context.Context) (DownloadStatistic, error) {
+	var res DownloadStatistic
+	err := d.c.Call(ctx, DownloadStatisticGetInfo{}, &res)
+	return res, err
+}
+
+// maxBulkIDsLength is the longest comma joined list of IDs sent in a single
+// request by CallDownloadTaskBulk, which leaves room for the other parameters
+// and the encoding overhead within maxGetURLLength.
+const maxBulkIDsLength = maxGetURLLength / 2
+
+// chunkIDs splits the IDs into chunks whose comma joined length is at most
+// max. An ID longer than max is put in a chunk by itself.
+func chunkIDs(ids []string, max int) [][]string {
+	var chunks [][]string
+	start, length := 0, 0
+	for i, id := range ids {
+		if i > start && length+1+len(id) > max {
+			chunks = append(chunks, ids[start:i])
+			start, length = i, 0
+		}
+		if i > start {
+			length++
+		}
+		length += len(id)
+	}
+	if start < len(ids) {
+		chunks = append(chunks, ids[start:])
+	}
+	return chunks
+}
+
+// CallDownloadTaskBulk makes the request returned by newRequest for the IDs,
+// as CallDownloadTask does, splitting large sets of IDs across multiple
+// requests. The per task results of all the requests are merged. If a request
+// fails, the results so far are returned along with the error. No requests are
+// made if there are no IDs.
+func (c *Client) CallDownloadTaskBulk(
+	ctx context.Context,
+	ids []string,
+	newRequest func(ids []string) MarshalRequest,
+) (DownloadTaskResults, error) {
+	var results DownloadTaskResults
+	for _, chunk := range chunkIDs(ids, maxBulkIDsLength) {
+		var res DownloadTaskResults
+		if err := c.CallDownloadTask(ctx, newRequest(chunk), &res); err != nil {
+			return results, err
+		}
+		results = append(results, res...)
+	}
+	return results, nil
+}
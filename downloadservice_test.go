@@ -0,0 +1,164 @@
+package syno
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func downloadStationService(t *testing.T, handle func(url.Values) string) *DownloadStation {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.Nil(t, r.ParseForm())
+			data := `{"SYNO.DownloadStation.Task":{"path":"DownloadStation/task.cgi"}}`
+			if r.Form.Get("api") != apiInfoAPI {
+				data = handle(r.Form)
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":true,"data":` + data + `}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c.DownloadStation()
+}
+
+func TestDownloadStationList(t *testing.T) {
+	ds := downloadStationService(t, func(v url.Values) string {
+		ensure.DeepEqual(t, v.Get("method"), "list")
+		ensure.DeepEqual(t, v.Get("additional"), "detail,transfer")
+		return `{"total":1,"offset":0,"tasks":[{"id":"a","status":"paused"}]}`
+	})
+	tasks, err := ds.List(
+		context.Background(),
+		DownloadTaskAdditionalDetail,
+		DownloadTaskAdditionalTransfer,
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, tasks, []DownloadTask{{ID: "a", Status: DownloadTaskPaused}})
+}
+
+func TestDownloadStationActions(t *testing.T) {
+	var methods []string
+	ds := downloadStationService(t, func(v url.Values) string {
+		methods = append(methods, v.Get("method"))
+		switch v.Get("method") {
+		case "create":
+			ensure.DeepEqual(t, v.Get("uri"), "magnet:?a")
+			ensure.DeepEqual(t, v.Get("destination"), "d")
+			return "null"
+		case "getinfo":
+			return `{"tasks":[{"id":"a"}]}`
+		}
+		ensure.DeepEqual(t, v.Get("id"), "a,b")
+		return `[{"id":"a","error":0},{"id":"b","error":0}]`
+	})
+	ctx := context.Background()
+	ensure.Nil(t, ds.Create(ctx, "magnet:?a", "d"))
+	tasks, err := ds.Get(ctx, "a")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, tasks, []DownloadTask{{ID: "a"}})
+	want := DownloadTaskResults{{ID: "a"}, {ID: "b"}}
+	res, err := ds.Pause(ctx, "a", "b")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, want)
+	res, err = ds.Resume(ctx, "a", "b")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, want)
+	res, err = ds.Delete(ctx, "a", "b")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, want)
+	ensure.DeepEqual(t, methods, []string{"create", "getinfo", "pause", "resume", "delete"})
+}
+
+func TestDownloadStationStats(t *testing.T) {
+	ds := downloadStationService(t, func(v url.Values) string {
+		ensure.DeepEqual(t, v.Get("api"), downloadStatisticAPI)
+		return `{"speed_download":1024,"speed_upload":"512"}`
+	})
+	stats, err := ds.Stats(context.Background())
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, stats, DownloadStatistic{SpeedDownload: 1024, SpeedUpload: 512})
+}
+
+func TestDownloadStationFind(t *testing.T) {
+	ds := downloadStationService(t, func(v url.Values) string {
+		ensure.DeepEqual(t, v.Get("status"), "")
+		ensure.DeepEqual(t, v.Get("type"), "")
+		return `{"total":4,"offset":0,"tasks":[
+			{"id":"a","type":"bt","status":"finished"},
+			{"id":"b","type":"bt","status":"downloading"},
+			{"id":"c","type":"http","status":"downloading"},
+			{"id":"d","type":"bt","status":"waiting"}
+		]}`
+	})
+	tasks, err := ds.Find(context.Background(), DownloadTaskFilter{
+		Status: []DownloadTaskStatus{DownloadTaskDownloading, DownloadTaskWaiting},
+		Type:   []DownloadTaskType{DownloadTaskBT},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, tasks, []DownloadTask{
+		{ID: "b", Type: DownloadTaskBT, Status: DownloadTaskDownloading},
+		{ID: "d", Type: DownloadTaskBT, Status: DownloadTaskWaiting},
+	})
+}
+
+func TestChunkIDs(t *testing.T) {
+	cases := []struct {
+		IDs    []string
+		Max    int
+		Chunks [][]string
+	}{
+		{IDs: nil, Max: 5},
+		{IDs: []string{"a"}, Max: 5, Chunks: [][]string{{"a"}}},
+		{
+			IDs:    []string{"a", "b", "c", "d"},
+			Max:    5,
+			Chunks: [][]string{{"a", "b", "c"}, {"d"}},
+		},
+		{
+			IDs:    []string{"toolong", "a", "b"},
+			Max:    5,
+			Chunks: [][]string{{"toolong"}, {"a", "b"}},
+		},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, chunkIDs(c.IDs, c.Max), c.Chunks, c.IDs)
+	}
+}
+
+func TestCallDownloadTaskBulk(t *testing.T) {
+	ids := make([]string, 500)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("dbid_%d", i)
+	}
+	var requests int
+	ds := downloadStationService(t, func(v url.Values) string {
+		requests++
+		ensure.True(t, len(v.Get("id")) <= maxBulkIDsLength)
+		var res []string
+		for _, id := range strings.Split(v.Get("id"), ",") {
+			code := 0
+			if id == "dbid_499" {
+				code = int(ErrorDownloadInvalidTaskID)
+			}
+			res = append(res, fmt.Sprintf(`{"id":%q,"error":%d}`, id, code))
+		}
+		return "[" + strings.Join(res, ",") + "]"
+	})
+	res, err := ds.Pause(context.Background(), ids...)
+	ensure.Nil(t, err)
+	ensure.True(t, requests > 1, requests)
+	ensure.DeepEqual(t, len(res), len(ids))
+	ensure.DeepEqual(t, res.Failed(), DownloadTaskResults{
+		{ID: "dbid_499", Error: ErrorDownloadInvalidTaskID},
+	})
+}
@@ -0,0 +1,474 @@
+package syno
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	downloadInfoPath    = "/webapi/DownloadStation/info.cgi"
+	downloadInfoAPI     = "SYNO.DownloadStation.Info"
+	downloadInfoVersion = "1"
+
+	downloadStatisticPath    = "/webapi/DownloadStation/statistic.cgi"
+	downloadStatisticAPI     = "SYNO.DownloadStation.Statistic"
+	downloadStatisticVersion = "1"
+
+	downloadSchedulePath    = "/webapi/DownloadStation/schedule.cgi"
+	downloadScheduleAPI     = "SYNO.DownloadStation.Schedule"
+	downloadScheduleVersion = "1"
+
+	downloadRSSSitePath    = "/webapi/DownloadStation/RSSsite.cgi"
+	downloadRSSSiteAPI     = "SYNO.DownloadStation.RSS.Site"
+	downloadRSSSiteVersion = "1"
+
+	downloadRSSFeedPath    = "/webapi/DownloadStation/RSSfeed.cgi"
+	downloadRSSFeedAPI     = "SYNO.DownloadStation.RSS.Feed"
+	downloadRSSFeedVersion = "1"
+
+	downloadBTSearchPath    = "/webapi/DownloadStation/btsearch.cgi"
+	downloadBTSearchAPI     = "SYNO.DownloadStation.BTSearch"
+	downloadBTSearchVersion = "1"
+)
+
+// downloadStationRequest builds the Request for a DownloadStation API method
+// with the parameters from v.
+func downloadStationRequest(path, api, version, method string, v interface{}) (*Request, error) {
+	p, err := MarshalParams(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		Path:    path,
+		API:     api,
+		Version: version,
+		Method:  method,
+		Params:  p,
+		Session: SessionDownloadStation,
+	}, nil
+}
+
+func downloadInfoRequest(method string, v interface{}) (*Request, error) {
+	return downloadStationRequest(
+		downloadInfoPath, downloadInfoAPI, downloadInfoVersion, method, v)
+}
+
+// DownloadStationGetInfo gets information about Download Station. The
+// response is DownloadStationInfo.
+type DownloadStationGetInfo struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadStationGetInfo) MarshalRequest() (*Request, error) {
+	r, err := downloadInfoRequest("getinfo", d)
+	if err != nil {
+		return nil, err
+	}
+	r.Cacheable = true
+	return r, nil
+}
+
+// DownloadStationInfo is the response for DownloadStationGetInfo.
+type DownloadStationInfo struct {
+	Version       int    `json:"version"`
+	VersionString string `json:"version_string"`
+
+	// IsManager reports if the user can change the Download Station settings.
+	IsManager bool `json:"is_manager"`
+}
+
+// DownloadStationGetConfig gets the Download Station settings. The response
+// is DownloadStationConfig.
+type DownloadStationGetConfig struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadStationGetConfig) MarshalRequest() (*Request, error) {
+	return downloadInfoRequest("getconfig", d)
+}
+
+// DownloadStationConfig is the response for DownloadStationGetConfig. The
+// rates are in KB/s, where 0 means unlimited.
+type DownloadStationConfig struct {
+	BTMaxDownload           int    `json:"bt_max_download"`
+	BTMaxUpload             int    `json:"bt_max_upload"`
+	EMuleMaxDownload        int    `json:"emule_max_download"`
+	EMuleMaxUpload          int    `json:"emule_max_upload"`
+	NZBMaxDownload          int    `json:"nzb_max_download"`
+	HTTPMaxDownload         int    `json:"http_max_download"`
+	FTPMaxDownload          int    `json:"ftp_max_download"`
+	EMuleEnabled            bool   `json:"emule_enabled"`
+	UnzipServiceEnabled     bool   `json:"unzip_service_enabled"`
+	DefaultDestination      string `json:"default_destination"`
+	EMuleDefaultDestination string `json:"emule_default_destination"`
+}
+
+// DownloadStationSetServerConfig changes the Download Station settings. Only
+// the fields that are set are changed, the rates are in KB/s where 0 means
+// unlimited. It requires a user for which DownloadStationInfo.IsManager is
+// true, and does not have a response.
+type DownloadStationSetServerConfig struct {
+	BTMaxDownload           *int    `syno:"bt_max_download"`
+	BTMaxUpload             *int    `syno:"bt_max_upload"`
+	EMuleMaxDownload        *int    `syno:"emule_max_download"`
+	EMuleMaxUpload          *int    `syno:"emule_max_upload"`
+	NZBMaxDownload          *int    `syno:"nzb_max_download"`
+	HTTPMaxDownload         *int    `syno:"http_max_download"`
+	FTPMaxDownload          *int    `syno:"ftp_max_download"`
+	EMuleEnabled            *bool   `syno:"emule_enabled"`
+	UnzipServiceEnabled     *bool   `syno:"unzip_service_enabled"`
+	DefaultDestination      *string `syno:"default_destination"`
+	EMuleDefaultDestination *string `syno:"emule_default_destination"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadStationSetServerConfig) MarshalRequest() (*Request, error) {
+	r, err := downloadInfoRequest("setserverconfig", d)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+// DownloadEMuleSetConfig changes only the eMule settings of Download Station,
+// leaving the others as they are. The rates are in KB/s where 0 means
+// unlimited. It does not have a response.
+type DownloadEMuleSetConfig struct {
+	Enabled            *bool   `syno:"emule_enabled"`
+	MaxDownload        *int    `syno:"emule_max_download"`
+	MaxUpload          *int    `syno:"emule_max_upload"`
+	DefaultDestination *string `syno:"emule_default_destination"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadEMuleSetConfig) MarshalRequest() (*Request, error) {
+	r, err := downloadInfoRequest("setserverconfig", d)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+// DownloadEMuleTaskCreate creates download tasks from ed2k links. The tasks are
+// saved to the eMule default destination unless Destination is given, and
+// require eMule to be enabled. It does not have a response.
+type DownloadEMuleTaskCreate struct {
+	Links       []string `syno:"uri"`
+	Destination string   `syno:"destination,omitempty"`
+}
+
+// Validate checks that at least one link is given, and that all of them are
+// ed2k links.
+func (d DownloadEMuleTaskCreate) Validate() error {
+	if len(d.Links) == 0 {
+		return &ValidationError{Field: "Links", Reason: "required"}
+	}
+	for _, l := range d.Links {
+		if !strings.HasPrefix(strings.ToLower(l), "ed2k://") {
+			return &ValidationError{Field: "Links", Reason: "not an ed2k link: " + l}
+		}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadEMuleTaskCreate) MarshalRequest() (*Request, error) {
+	return downloadTaskAction("create", d)
+}
+
+// DownloadStatisticGetInfo gets the total transfer speeds of Download
+// Station. The response is DownloadStatistic.
+type DownloadStatisticGetInfo struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadStatisticGetInfo) MarshalRequest() (*Request, error) {
+	return downloadStationRequest(
+		downloadStatisticPath, downloadStatisticAPI, downloadStatisticVersion, "getinfo", d)
+}
+
+// DownloadStatistic is the response for DownloadStatisticGetInfo. The speeds
+// are in bytes per second, and the eMule ones are only included if eMule is
+// enabled.
+type DownloadStatistic struct {
+	SpeedDownload      Bytes `json:"speed_download"`
+	SpeedUpload        Bytes `json:"speed_upload"`
+	EMuleSpeedDownload Bytes `json:"emule_speed_download"`
+	EMuleSpeedUpload   Bytes `json:"emule_speed_upload"`
+}
+
+func downloadScheduleRequest(method string, v interface{}) (*Request, error) {
+	return downloadStationRequest(
+		downloadSchedulePath, downloadScheduleAPI, downloadScheduleVersion, method, v)
+}
+
+// DownloadScheduleGetConfig gets the Download Station schedule settings. The
+// response is DownloadScheduleConfig.
+type DownloadScheduleGetConfig struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadScheduleGetConfig) MarshalRequest() (*Request, error) {
+	return downloadScheduleRequest("getconfig", d)
+}
+
+// DownloadScheduleConfig is the response for DownloadScheduleGetConfig.
+// Enabled and EMuleEnabled report if the download schedule applies to regular
+// and eMule downloads respectively.
+type DownloadScheduleConfig struct {
+	Enabled      bool `json:"enabled"`
+	EMuleEnabled bool `json:"emule_enabled"`
+}
+
+// DownloadScheduleSetConfig changes the Download Station schedule settings.
+// Only the fields that are set are changed. It does not have a response.
+type DownloadScheduleSetConfig struct {
+	Enabled      *bool `syno:"enabled"`
+	EMuleEnabled *bool `syno:"emule_enabled"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadScheduleSetConfig) MarshalRequest() (*Request, error) {
+	r, err := downloadScheduleRequest("setconfig", d)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+func downloadRSSSiteRequest(method string, v interface{}) (*Request, error) {
+	return downloadStationRequest(
+		downloadRSSSitePath, downloadRSSSiteAPI, downloadRSSSiteVersion, method, v)
+}
+
+// DownloadRSSSiteList lists the RSS sites. The response is
+// DownloadRSSSiteListResponse.
+type DownloadRSSSiteList struct {
+	Offset int `syno:"offset,omitempty"`
+	Limit  int `syno:"limit,omitempty"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadRSSSiteList) MarshalRequest() (*Request, error) {
+	return downloadRSSSiteRequest("list", d)
+}
+
+// DownloadRSSSite is an RSS site as returned by DownloadRSSSiteList.
+type DownloadRSSSite struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Username    string `json:"username"`
+	IsUpdating  bool   `json:"is_updating"`
+	RefreshTime Time   `json:"refresh_time"`
+}
+
+// DownloadRSSSiteListResponse is the response from a DownloadRSSSiteList
+// request.
+type DownloadRSSSiteListResponse = ListResponse[DownloadRSSSite]
+
+// DownloadRSSSiteRefresh refreshes RSS sites, or all of them if IDs is empty.
+// It does not have a response.
+type DownloadRSSSiteRefresh struct {
+	IDs []int `syno:"id,omitempty"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadRSSSiteRefresh) MarshalRequest() (*Request, error) {
+	r, err := downloadRSSSiteRequest("refresh", d)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.IDs) == 0 {
+		r.Params.Set("id", "ALL")
+	}
+	return r, nil
+}
+
+// DownloadRSSFeedList lists the feed items of an RSS site. The response is
+// DownloadRSSFeedListResponse.
+type DownloadRSSFeedList struct {
+	ID     int `syno:"id"`
+	Offset int `syno:"offset,omitempty"`
+	Limit  int `syno:"limit,omitempty"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadRSSFeedList) MarshalRequest() (*Request, error) {
+	return downloadStationRequest(
+		downloadRSSFeedPath, downloadRSSFeedAPI, downloadRSSFeedVersion, "list", d)
+}
+
+// DownloadRSSFeed is an item of an RSS site as returned by
+// DownloadRSSFeedList.
+type DownloadRSSFeed struct {
+	Title        string `json:"title"`
+	Size         Bytes  `json:"size"`
+	Time         Time   `json:"time"`
+	DownloadURI  string `json:"download_uri"`
+	ExternalLink string `json:"external_link"`
+}
+
+// TaskCreate returns the request to create a download task for the item.
+func (f DownloadRSSFeed) TaskCreate() DownloadTaskCreate {
+	return DownloadTaskCreate{URI: f.DownloadURI}
+}
+
+// DownloadRSSFeedListResponse is the response from a DownloadRSSFeedList
+// request.
+type DownloadRSSFeedListResponse = ListResponse[DownloadRSSFeed]
+
+func downloadBTSearchRequest(method string, v interface{}) (*Request, error) {
+	return downloadStationRequest(
+		downloadBTSearchPath, downloadBTSearchAPI, downloadBTSearchVersion, method, v)
+}
+
+// DownloadBTSearchStart starts a BitTorrent search for the keyword. The search
+// runs in the background and its results are fetched with
+// DownloadBTSearchList. The response is DownloadBTSearchStartResponse.
+type DownloadBTSearchStart struct {
+	Keyword string `syno:"keyword"`
+
+	// Module is the search module to use, or all enabled ones if empty.
+	Module string `syno:"module"`
+}
+
+// Validate checks that the keyword is given.
+func (d DownloadBTSearchStart) Validate() error {
+	return requireField("Keyword", d.Keyword)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadBTSearchStart) MarshalRequest() (*Request, error) {
+	if d.Module == "" {
+		d.Module = "enabled"
+	}
+	return downloadBTSearchRequest("start", d)
+}
+
+// DownloadBTSearchStartResponse is the response for DownloadBTSearchStart.
+type DownloadBTSearchStartResponse struct {
+	TaskID string `json:"taskid"`
+}
+
+// DownloadBTSearchList lists the results found so far by a search. The
+// response is DownloadBTSearchListResponse.
+type DownloadBTSearchList struct {
+	TaskID         string        `syno:"taskid"`
+	Offset         int           `syno:"offset,omitempty"`
+	Limit          int           `syno:"limit,omitempty"`
+	SortBy         string        `syno:"sort_by,omitempty"`
+	SortDirection  SortDirection `syno:"sort_direction,omitempty"`
+	FilterCategory string        `syno:"filter_category,omitempty"`
+	FilterTitle    string        `syno:"filter_title,omitempty"`
+}
+
+// Validate checks that the search task ID is given.
+func (d DownloadBTSearchList) Validate() error {
+	return requireField("TaskID", d.TaskID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadBTSearchList) MarshalRequest() (*Request, error) {
+	return downloadBTSearchRequest("list", d)
+}
+
+// DownloadBTSearchResult is a torrent found by a search.
+type DownloadBTSearchResult struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	Size         Bytes  `json:"size"`
+	Date         string `json:"date"`
+	Seeds        int    `json:"seeds"`
+	Peers        int    `json:"peers"`
+	Leechs       int    `json:"leechs"`
+	ModuleID     string `json:"module_id"`
+	ModuleTitle  string `json:"module_title"`
+	DownloadURI  string `json:"download_uri"`
+	ExternalLink string `json:"external_link"`
+}
+
+// TaskCreate returns the request to create a download task for the result.
+func (r DownloadBTSearchResult) TaskCreate() DownloadTaskCreate {
+	return DownloadTaskCreate{URI: r.DownloadURI}
+}
+
+// DownloadBTSearchListResponse is the response for DownloadBTSearchList.
+// Finished reports if the search has completed, otherwise more results may be
+// returned by listing again.
+type DownloadBTSearchListResponse struct {
+	ListResponse[DownloadBTSearchResult]
+	Finished bool
+}
+
+// UnmarshalJSON decodes the response.
+func (r *DownloadBTSearchListResponse) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Finished bool `json:"finished"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &r.ListResponse); err != nil {
+		return err
+	}
+	r.Finished = v.Finished
+	return nil
+}
+
+// DownloadBTSearchClean removes the results of searches. It does not have a
+// response.
+type DownloadBTSearchClean struct {
+	TaskIDs []string `syno:"taskid"`
+}
+
+// Validate checks that at least one search task ID is given.
+func (d DownloadBTSearchClean) Validate() error {
+	return requireField("TaskIDs", strings.Join(d.TaskIDs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadBTSearchClean) MarshalRequest() (*Request, error) {
+	return downloadBTSearchRequest("clean", d)
+}
+
+// DownloadBTSearchGetCategory gets the categories results can be filtered by.
+// The response is DownloadBTSearchCategories.
+type DownloadBTSearchGetCategory struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadBTSearchGetCategory) MarshalRequest() (*Request, error) {
+	return downloadBTSearchRequest("getCategory", d)
+}
+
+// DownloadBTSearchCategories is the response for DownloadBTSearchGetCategory.
+type DownloadBTSearchCategories struct {
+	Categories []DownloadBTSearchCategory `json:"categories"`
+}
+
+// DownloadBTSearchCategory is a search result category.
+type DownloadBTSearchCategory struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// DownloadBTSearchGetModule gets the search modules. The response is
+// DownloadBTSearchModules.
+type DownloadBTSearchGetModule struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadBTSearchGetModule) MarshalRequest() (*Request, error) {
+	return downloadBTSearchRequest("getModule", d)
+}
+
+// DownloadBTSearchModules is the response for DownloadBTSearchGetModule.
+type DownloadBTSearchModules struct {
+	Modules []DownloadBTSearchModule `json:"modules"`
+}
+
+// DownloadBTSearchModule is a search module.
+type DownloadBTSearchModule struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Enabled bool   `json:"enabled"`
+}
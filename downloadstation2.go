@@ -0,0 +1,257 @@
+package syno
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/daaku/syno/params"
+)
+
+const (
+	downloadTask2Path    = "/webapi/entry.cgi"
+	downloadTask2API     = "SYNO.DownloadStation2.Task"
+	downloadTask2Version = "2"
+)
+
+func downloadTask2Request(method string, p url.Values) *Request {
+	return &Request{
+		Path:       downloadTask2Path,
+		API:        downloadTask2API,
+		Version:    downloadTask2Version,
+		Method:     method,
+		HTTPMethod: http.MethodPost,
+		Params:     p,
+		Session:    SessionDownloadStation,
+	}
+}
+
+// DownloadTask2List is the SYNO.DownloadStation2 equivalent of
+// DownloadTaskList. The response is DownloadTaskListResponse.
+type DownloadTask2List struct {
+	Offset     int      `syno:"offset,omitempty"`
+	Limit      int      `syno:"limit,omitempty"`
+	Additional []string `syno:"additional,omitempty,json"`
+
+	SortBy        DownloadTaskSortBy `syno:"sort_by,omitempty"`
+	SortDirection SortDirection      `syno:"order,omitempty"`
+
+	// Status only selects tasks with the given statuses. The API filters by
+	// numeric status, which DownloadTaskError does not have, so no status
+	// filter is sent if it is included.
+	Status []DownloadTaskStatus `syno:"-"`
+
+	// Type only selects tasks with the given types.
+	Type []DownloadTaskType `syno:"type,omitempty,json"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTask2List) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(d)
+	if err != nil {
+		return nil, err
+	}
+	if codes, ok := downloadTask2StatusCodes(d.Status); ok {
+		if err := params.SetJSON(p, "status", codes); err != nil {
+			return nil, err
+		}
+	}
+	r := downloadTask2Request("list", p)
+	r.HTTPMethod = ""
+	return r, nil
+}
+
+// downloadTask2StatusCodes returns the numeric statuses to filter by, or
+// false if there are none or one cannot be sent.
+func downloadTask2StatusCodes(statuses []DownloadTaskStatus) ([]int, bool) {
+	if len(statuses) == 0 {
+		return nil, false
+	}
+	codes := make([]int, len(statuses))
+	for i, s := range statuses {
+		code, ok := downloadTask2StatusCode(s)
+		if !ok {
+			return nil, false
+		}
+		codes[i] = code
+	}
+	return codes, true
+}
+
+// DownloadTask2Create is the SYNO.DownloadStation2 equivalent of
+// DownloadTaskCreate. It does not have a response.
+type DownloadTask2Create struct {
+	URLs []string
+
+	// Destination is the shared folder path to save the tasks to, or the
+	// default destination if empty.
+	Destination string
+
+	// File is a torrent or NZB file to create the task from instead of URLs.
+	File io.Reader
+	// FileName is the name the File is uploaded with.
+	FileName string
+}
+
+// Validate checks that exactly one of URLs and File is set.
+func (d DownloadTask2Create) Validate() error {
+	if d.File != nil {
+		if len(d.URLs) > 0 {
+			return &ValidationError{Field: "File", Reason: "conflicts with URLs"}
+		}
+		return nil
+	}
+	return requireField("URLs", strings.Join(d.URLs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTask2Create) MarshalRequest() (*Request, error) {
+	p := url.Values{}
+	if err := params.SetJSON(p, "destination", d.Destination); err != nil {
+		return nil, err
+	}
+	p.Set("create_list", "false")
+	r := downloadTask2Request("create", p)
+	if d.File != nil {
+		p.Set("type", "file")
+		p.Set("file", `["torrent"]`)
+		r.Files = []RequestFile{{Name: "torrent", Filename: d.FileName, Body: d.File}}
+		return r, nil
+	}
+	p.Set("type", "url")
+	params.SetJSONArray(p, "url", d.URLs)
+	return r, nil
+}
+
+// DownloadTask2Delete is the SYNO.DownloadStation2 equivalent of
+// DownloadTaskDelete. The response is DownloadTaskResults.
+type DownloadTask2Delete struct {
+	IDs           []string `syno:"id,json"`
+	ForceComplete bool     `syno:"force_complete"`
+}
+
+// Validate checks that at least one task ID is given.
+func (d DownloadTask2Delete) Validate() error {
+	return requireField("IDs", strings.Join(d.IDs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTask2Delete) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(d)
+	if err != nil {
+		return nil, err
+	}
+	return downloadTask2Request("delete", p), nil
+}
+
+// DownloadTask2Pause is the SYNO.DownloadStation2 equivalent of
+// DownloadTaskPause. The response is DownloadTaskResults.
+type DownloadTask2Pause struct {
+	IDs []string `syno:"id,json"`
+}
+
+// Validate checks that at least one task ID is given.
+func (d DownloadTask2Pause) Validate() error {
+	return requireField("IDs", strings.Join(d.IDs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTask2Pause) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(d)
+	if err != nil {
+		return nil, err
+	}
+	return downloadTask2Request("pause", p), nil
+}
+
+// DownloadTask2Resume is the SYNO.DownloadStation2 equivalent of
+// DownloadTaskResume. The response is DownloadTaskResults.
+type DownloadTask2Resume struct {
+	IDs []string `syno:"id,json"`
+}
+
+// Validate checks that at least one task ID is given.
+func (d DownloadTask2Resume) Validate() error {
+	return requireField("IDs", strings.Join(d.IDs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTask2Resume) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(d)
+	if err != nil {
+		return nil, err
+	}
+	return downloadTask2Request("resume", p), nil
+}
+
+// downloadStation2Request is implemented by SYNO.DownloadStation.Task requests
+// that have a SYNO.DownloadStation2.Task equivalent. It reports false if the
+// request uses options the equivalent does not support.
+type downloadStation2Request interface {
+	downloadStation2() (MarshalRequest, bool)
+}
+
+func (d DownloadTaskList) downloadStation2() (MarshalRequest, bool) {
+	return DownloadTask2List(d), true
+}
+
+func (d DownloadTaskCreate) downloadStation2() (MarshalRequest, bool) {
+	if d.Username != "" || d.Password != "" || d.UnzipPassword != "" {
+		return nil, false
+	}
+	r := DownloadTask2Create{
+		Destination: d.Destination,
+		File:        d.File,
+		FileName:    d.FileName,
+	}
+	if d.URI != "" {
+		r.URLs = strings.Split(d.URI, ",")
+	}
+	return r, true
+}
+
+func (d DownloadTaskDelete) downloadStation2() (MarshalRequest, bool) {
+	return DownloadTask2Delete(d), true
+}
+
+func (d DownloadTaskPause) downloadStation2() (MarshalRequest, bool) {
+	return DownloadTask2Pause(d), true
+}
+
+func (d DownloadTaskResume) downloadStation2() (MarshalRequest, bool) {
+	return DownloadTask2Resume(d), true
+}
+
+// CallDownloadTask makes the SYNO.DownloadStation.Task request, using the
+// equivalent SYNO.DownloadStation2.Task request instead if the server provides
+// that API, as DSM 7 does. Requests without an equivalent are made as is.
+func (c *Client) CallDownloadTask(ctx context.Context, r MarshalRequest, v interface{}) error {
+	r, err := c.downloadTaskRequest(ctx, r)
+	if err != nil {
+		return err
+	}
+	return c.Call(ctx, r, v)
+}
+
+// downloadTaskRequest returns the request to make for the
+// SYNO.DownloadStation.Task request, as described by CallDownloadTask.
+func (c *Client) downloadTaskRequest(ctx context.Context, r MarshalRequest) (MarshalRequest, error) {
+	d, ok := r.(downloadStation2Request)
+	if !ok {
+		return r, nil
+	}
+	r2, ok := d.downloadStation2()
+	if !ok {
+		return r, nil
+	}
+	has, err := c.HasAPI(ctx, downloadTask2API)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		return r2, nil
+	}
+	return r, nil
+}
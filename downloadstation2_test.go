@@ -0,0 +1,227 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestDownloadTask2Marshal(t *testing.T) {
+	cases := []struct {
+		Request    MarshalRequest
+		Method     string
+		HTTPMethod string
+		Params     url.Values
+	}{
+		{
+			Request: DownloadTask2List{Additional: []string{"detail", "transfer"}},
+			Method:  "list",
+			Params:  url.Values{"additional": []string{`["detail","transfer"]`}},
+		},
+		{
+			Request: DownloadTask2List{
+				SortBy:        DownloadTaskSortCreated,
+				SortDirection: SortDescending,
+				Status:        []DownloadTaskStatus{DownloadTaskDownloading, DownloadTaskWaiting},
+				Type:          []DownloadTaskType{DownloadTaskBT},
+			},
+			Method: "list",
+			Params: url.Values{
+				"sort_by": []string{"created_time"},
+				"order":   []string{"desc"},
+				"status":  []string{"[2,1]"},
+				"type":    []string{`["bt"]`},
+			},
+		},
+		{
+			Request: DownloadTask2List{
+				Status: []DownloadTaskStatus{DownloadTaskDownloading, DownloadTaskError},
+			},
+			Method: "list",
+			Params: url.Values{},
+		},
+		{
+			Request:    DownloadTask2Create{URLs: []string{"http://a", "magnet:?b"}},
+			Method:     "create",
+			HTTPMethod: "POST",
+			Params: url.Values{
+				"type":        []string{"url"},
+				"url":         []string{`["http://a","magnet:?b"]`},
+				"destination": []string{`""`},
+				"create_list": []string{"false"},
+			},
+		},
+		{
+			Request:    DownloadTask2Delete{IDs: []string{"dbid_1"}},
+			Method:     "delete",
+			HTTPMethod: "POST",
+			Params: url.Values{
+				"id":             []string{`["dbid_1"]`},
+				"force_complete": []string{"false"},
+			},
+		},
+		{
+			Request:    DownloadTask2Pause{IDs: []string{"dbid_1", "dbid_2"}},
+			Method:     "pause",
+			HTTPMethod: "POST",
+			Params:     url.Values{"id": []string{`["dbid_1","dbid_2"]`}},
+		},
+		{
+			Request:    DownloadTask2Resume{IDs: []string{"dbid_1"}},
+			Method:     "resume",
+			HTTPMethod: "POST",
+			Params:     url.Values{"id": []string{`["dbid_1"]`}},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:       downloadTask2Path,
+			API:        downloadTask2API,
+			Version:    downloadTask2Version,
+			Method:     c.Method,
+			HTTPMethod: c.HTTPMethod,
+			Params:     c.Params,
+			Session:    SessionDownloadStation,
+		})
+	}
+}
+
+func TestDownloadTask2CreateFileMarshal(t *testing.T) {
+	f := strings.NewReader("torrent")
+	r, err := DownloadTask2Create{
+		File:        f,
+		FileName:    "a.torrent",
+		Destination: "downloads",
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Params, url.Values{
+		"type":        []string{"file"},
+		"file":        []string{`["torrent"]`},
+		"destination": []string{`"downloads"`},
+		"create_list": []string{"false"},
+	})
+	ensure.DeepEqual(t, r.Files, []RequestFile{
+		{Name: "torrent", Filename: "a.torrent", Body: f},
+	})
+}
+
+func TestDownloadStation2Equivalent(t *testing.T) {
+	cases := []struct {
+		Request downloadStation2Request
+		Want    MarshalRequest
+	}{
+		{
+			Request: DownloadTaskList{Limit: 1, Additional: []string{"detail"}},
+			Want:    DownloadTask2List{Limit: 1, Additional: []string{"detail"}},
+		},
+		{
+			Request: DownloadTaskList{
+				SortBy: DownloadTaskSortSize,
+				Status: []DownloadTaskStatus{DownloadTaskPaused},
+			},
+			Want: DownloadTask2List{
+				SortBy: DownloadTaskSortSize,
+				Status: []DownloadTaskStatus{DownloadTaskPaused},
+			},
+		},
+		{
+			Request: DownloadTaskCreate{URI: "a,b", Destination: "c"},
+			Want:    DownloadTask2Create{URLs: []string{"a", "b"}, Destination: "c"},
+		},
+		{
+			Request: DownloadTaskCreate{URI: "a", Password: "b"},
+		},
+		{
+			Request: DownloadTaskDelete{IDs: []string{"a"}, ForceComplete: true},
+			Want:    DownloadTask2Delete{IDs: []string{"a"}, ForceComplete: true},
+		},
+		{
+			Request: DownloadTaskPause{IDs: []string{"a"}},
+			Want:    DownloadTask2Pause{IDs: []string{"a"}},
+		},
+		{
+			Request: DownloadTaskResume{IDs: []string{"a"}},
+			Want:    DownloadTask2Resume{IDs: []string{"a"}},
+		},
+	}
+	for _, c := range cases {
+		r, ok := c.Request.downloadStation2()
+		ensure.DeepEqual(t, ok, c.Want != nil)
+		ensure.DeepEqual(t, r, c.Want)
+	}
+}
+
+func downloadStation2Client(t *testing.T, apis map[string]interface{}, api *string) *Client {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.Nil(t, r.ParseForm())
+			if r.Form.Get("api") == apiInfoAPI {
+				b, _ := json.Marshal(map[string]interface{}{
+					"success": true,
+					"data":    apis,
+				})
+				return &http.Response{
+					Body: ioutil.NopCloser(strings.NewReader(string(b))),
+				}, nil
+			}
+			*api = r.Form.Get("api")
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": true,
+					"data": {"total": 1, "offset": 0, "task": [{"id": "a", "status": 2}]}
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+func TestClientCallDownloadTask(t *testing.T) {
+	var api string
+	c := downloadStation2Client(t, map[string]interface{}{
+		downloadTaskAPI:  map[string]interface{}{"path": "DownloadStation/task.cgi"},
+		downloadTask2API: map[string]interface{}{"path": "entry.cgi"},
+	}, &api)
+	var res DownloadTaskListResponse
+	ensure.Nil(t, c.CallDownloadTask(context.Background(), DownloadTaskList{}, &res))
+	ensure.DeepEqual(t, api, downloadTask2API)
+	ensure.DeepEqual(t, res.Items, []DownloadTask{
+		{ID: "a", Status: DownloadTaskDownloading},
+	})
+
+	c = downloadStation2Client(t, map[string]interface{}{
+		downloadTaskAPI: map[string]interface{}{"path": "DownloadStation/task.cgi"},
+	}, &api)
+	ensure.Nil(t, c.CallDownloadTask(context.Background(), DownloadTaskList{}, &res))
+	ensure.DeepEqual(t, api, downloadTaskAPI)
+}
+
+func TestDownloadTaskStatusUnmarshal(t *testing.T) {
+	cases := []struct {
+		JSON   string
+		Status DownloadTaskStatus
+	}{
+		{`"seeding"`, DownloadTaskSeeding},
+		{`1`, DownloadTaskWaiting},
+		{`5`, DownloadTaskFinished},
+		{`101`, DownloadTaskError},
+		{`7`, DownloadTaskStatus("7")},
+	}
+	for _, c := range cases {
+		var s DownloadTaskStatus
+		ensure.Nil(t, json.Unmarshal([]byte(c.JSON), &s))
+		ensure.DeepEqual(t, s, c.Status)
+	}
+	var s DownloadTaskStatus
+	ensure.NotNil(t, json.Unmarshal([]byte(`{}`), &s))
+}
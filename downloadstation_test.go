@@ -0,0 +1,376 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestDownloadStationInfoMarshal(t *testing.T) {
+	rate, enabled, dest := 0, true, "downloads"
+	cases := []struct {
+		Request   MarshalRequest
+		Method    string
+		HTTP      string
+		Params    url.Values
+		Cacheable bool
+	}{
+		{
+			Request:   DownloadStationGetInfo{},
+			Method:    "getinfo",
+			Params:    url.Values{},
+			Cacheable: true,
+		},
+		{
+			Request: DownloadStationGetConfig{},
+			Method:  "getconfig",
+			Params:  url.Values{},
+		},
+		{
+			Request: DownloadStationSetServerConfig{},
+			Method:  "setserverconfig",
+			HTTP:    "POST",
+			Params:  url.Values{},
+		},
+		{
+			Request: DownloadStationSetServerConfig{
+				BTMaxDownload:      &rate,
+				EMuleEnabled:       &enabled,
+				DefaultDestination: &dest,
+			},
+			Method: "setserverconfig",
+			HTTP:   "POST",
+			Params: url.Values{
+				"bt_max_download":     []string{"0"},
+				"emule_enabled":       []string{"true"},
+				"default_destination": []string{"downloads"},
+			},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:       downloadInfoPath,
+			API:        downloadInfoAPI,
+			Version:    downloadInfoVersion,
+			Method:     c.Method,
+			HTTPMethod: c.HTTP,
+			Params:     c.Params,
+			Cacheable:  c.Cacheable,
+			Session:    SessionDownloadStation,
+		})
+	}
+}
+
+func TestClientDownloadStationGetConfig(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": true,
+					"data": {
+						"bt_max_download": 100,
+						"bt_max_upload": 20,
+						"emule_enabled": false,
+						"unzip_service_enabled": true,
+						"default_destination": "downloads"
+					}
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	var config DownloadStationConfig
+	ensure.Nil(t, c.Call(context.Background(), DownloadStationGetConfig{}, &config))
+	ensure.DeepEqual(t, config, DownloadStationConfig{
+		BTMaxDownload:       100,
+		BTMaxUpload:         20,
+		UnzipServiceEnabled: true,
+		DefaultDestination:  "downloads",
+	})
+}
+
+func TestDownloadScheduleMarshal(t *testing.T) {
+	enabled := false
+	cases := []struct {
+		Request MarshalRequest
+		Method  string
+		HTTP    string
+		Params  url.Values
+	}{
+		{
+			Request: DownloadScheduleGetConfig{},
+			Method:  "getconfig",
+			Params:  url.Values{},
+		},
+		{
+			Request: DownloadScheduleSetConfig{Enabled: &enabled},
+			Method:  "setconfig",
+			HTTP:    "POST",
+			Params:  url.Values{"enabled": []string{"false"}},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:       downloadSchedulePath,
+			API:        downloadScheduleAPI,
+			Version:    downloadScheduleVersion,
+			Method:     c.Method,
+			HTTPMethod: c.HTTP,
+			Params:     c.Params,
+			Session:    SessionDownloadStation,
+		})
+	}
+}
+
+func TestDownloadRSSSiteMarshal(t *testing.T) {
+	cases := []struct {
+		Request MarshalRequest
+		Method  string
+		Params  url.Values
+	}{
+		{
+			Request: DownloadRSSSiteList{},
+			Method:  "list",
+			Params:  url.Values{},
+		},
+		{
+			Request: DownloadRSSSiteList{Offset: 10, Limit: 5},
+			Method:  "list",
+			Params: url.Values{
+				"offset": []string{"10"},
+				"limit":  []string{"5"},
+			},
+		},
+		{
+			Request: DownloadRSSSiteRefresh{},
+			Method:  "refresh",
+			Params:  url.Values{"id": []string{"ALL"}},
+		},
+		{
+			Request: DownloadRSSSiteRefresh{IDs: []int{1, 3}},
+			Method:  "refresh",
+			Params:  url.Values{"id": []string{"1,3"}},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:    downloadRSSSitePath,
+			API:     downloadRSSSiteAPI,
+			Version: downloadRSSSiteVersion,
+			Method:  c.Method,
+			Params:  c.Params,
+			Session: SessionDownloadStation,
+		})
+	}
+}
+
+func TestDownloadRSSSiteListResponseUnmarshal(t *testing.T) {
+	var res DownloadRSSSiteListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"offset": 0,
+		"total": 1,
+		"sites": [{
+			"id": 1,
+			"is_updating": false,
+			"refresh_time": "1273813078",
+			"title": "a",
+			"url": "http://a/rss",
+			"username": "admin"
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, DownloadRSSSiteListResponse{
+		Total: 1,
+		Items: []DownloadRSSSite{{
+			ID:          1,
+			Title:       "a",
+			URL:         "http://a/rss",
+			Username:    "admin",
+			RefreshTime: Time{time.Unix(1273813078, 0)},
+		}},
+	})
+}
+
+func TestDownloadRSSFeedListMarshal(t *testing.T) {
+	r, err := DownloadRSSFeedList{ID: 2, Limit: 10}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    downloadRSSFeedPath,
+		API:     downloadRSSFeedAPI,
+		Version: downloadRSSFeedVersion,
+		Method:  "list",
+		Params: url.Values{
+			"id":    []string{"2"},
+			"limit": []string{"10"},
+		},
+		Session: SessionDownloadStation,
+	})
+}
+
+func TestDownloadRSSFeedListResponseUnmarshal(t *testing.T) {
+	var res DownloadRSSFeedListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"offset": 0,
+		"total": 1,
+		"feeds": [{
+			"title": "a",
+			"size": "1024",
+			"time": "1273813078",
+			"download_uri": "http://a/a.torrent",
+			"external_link": "http://a/a"
+		}]
+	}`), &res))
+	feed := DownloadRSSFeed{
+		Title:        "a",
+		Size:         1024,
+		Time:         Time{time.Unix(1273813078, 0)},
+		DownloadURI:  "http://a/a.torrent",
+		ExternalLink: "http://a/a",
+	}
+	ensure.DeepEqual(t, res, DownloadRSSFeedListResponse{
+		Total: 1,
+		Items: []DownloadRSSFeed{feed},
+	})
+	ensure.DeepEqual(t, feed.TaskCreate(), DownloadTaskCreate{URI: "http://a/a.torrent"})
+}
+
+func TestDownloadBTSearchMarshal(t *testing.T) {
+	cases := []struct {
+		Request MarshalRequest
+		Method  string
+		Params  url.Values
+	}{
+		{
+			Request: DownloadBTSearchStart{Keyword: "a"},
+			Method:  "start",
+			Params: url.Values{
+				"keyword": []string{"a"},
+				"module":  []string{"enabled"},
+			},
+		},
+		{
+			Request: DownloadBTSearchList{
+				TaskID:        "t",
+				SortBy:        "seeds",
+				SortDirection: SortDescending,
+			},
+			Method: "list",
+			Params: url.Values{
+				"taskid":         []string{"t"},
+				"sort_by":        []string{"seeds"},
+				"sort_direction": []string{"desc"},
+			},
+		},
+		{
+			Request: DownloadBTSearchClean{TaskIDs: []string{"t", "u"}},
+			Method:  "clean",
+			Params:  url.Values{"taskid": []string{"t,u"}},
+		},
+		{
+			Request: DownloadBTSearchGetCategory{},
+			Method:  "getCategory",
+			Params:  url.Values{},
+		},
+		{
+			Request: DownloadBTSearchGetModule{},
+			Method:  "getModule",
+			Params:  url.Values{},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:    downloadBTSearchPath,
+			API:     downloadBTSearchAPI,
+			Version: downloadBTSearchVersion,
+			Method:  c.Method,
+			Params:  c.Params,
+			Session: SessionDownloadStation,
+		})
+	}
+}
+
+func TestDownloadBTSearchListResponseUnmarshal(t *testing.T) {
+	var res DownloadBTSearchListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"finished": true,
+		"offset": 0,
+		"total": 1,
+		"items": [{
+			"id": 1,
+			"title": "a",
+			"size": "2048",
+			"seeds": 5,
+			"module_id": "m",
+			"download_uri": "magnet:?a"
+		}]
+	}`), &res))
+	result := DownloadBTSearchResult{
+		ID:          1,
+		Title:       "a",
+		Size:        2048,
+		Seeds:       5,
+		ModuleID:    "m",
+		DownloadURI: "magnet:?a",
+	}
+	ensure.DeepEqual(t, res, DownloadBTSearchListResponse{
+		ListResponse: ListResponse[DownloadBTSearchResult]{
+			Total: 1,
+			Items: []DownloadBTSearchResult{result},
+		},
+		Finished: true,
+	})
+	ensure.DeepEqual(t, result.TaskCreate(), DownloadTaskCreate{URI: "magnet:?a"})
+	ensure.NotNil(t, json.Unmarshal([]byte(`{"finished":"x"}`), &res))
+}
+
+func TestDownloadEMuleMarshal(t *testing.T) {
+	enabled, dest := true, "emule"
+	r, err := DownloadEMuleSetConfig{
+		Enabled:            &enabled,
+		DefaultDestination: &dest,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       downloadInfoPath,
+		API:        downloadInfoAPI,
+		Version:    downloadInfoVersion,
+		Method:     "setserverconfig",
+		HTTPMethod: "POST",
+		Params: url.Values{
+			"emule_enabled":             []string{"true"},
+			"emule_default_destination": []string{"emule"},
+		},
+		Session: SessionDownloadStation,
+	})
+
+	r, err = DownloadEMuleTaskCreate{
+		Links: []string{"ed2k://|file|a|1|h|/", "ed2k://|file|b|1|h|/"},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       downloadTaskPath,
+		API:        downloadTaskAPI,
+		Version:    downloadTaskVersion,
+		Method:     "create",
+		HTTPMethod: "POST",
+		Params: url.Values{
+			"uri": []string{"ed2k://|file|a|1|h|/,ed2k://|file|b|1|h|/"},
+		},
+		Session: SessionDownloadStation,
+	})
+}
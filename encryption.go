@@ -0,0 +1,184 @@
+package syno
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/url"
+	"strconv"
+)
+
+const (
+	encryptionPath    = "/webapi/encryption.cgi"
+	encryptionAPI     = "SYNO.API.Encryption"
+	encryptionVersion = "1"
+)
+
+var errInvalidPublicKey = errors.New("syno: invalid encryption public key")
+
+// EncryptionInfo fetches the parameters needed to send encrypted requests.
+// The response is EncryptionInfoResponse.
+type EncryptionInfo struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (EncryptionInfo) MarshalRequest() (*Request, error) {
+	return &Request{
+		Path:    encryptionPath,
+		API:     encryptionAPI,
+		Version: encryptionVersion,
+		Method:  "getinfo",
+		Params: url.Values{
+			"format": []string{"module"},
+		},
+	}, nil
+}
+
+// EncryptionInfoResponse is the response from an EncryptionInfo request.
+type EncryptionInfoResponse struct {
+	CipherKey   string `json:"cipherkey"`
+	CipherToken string `json:"ciphertoken"`
+	PublicKey   string `json:"public_key"`
+	ServerTime  int64  `json:"server_time"`
+}
+
+func (e *EncryptionInfoResponse) publicKey() (*rsa.PublicKey, error) {
+	n, ok := new(big.Int).SetString(e.PublicKey, 16)
+	if !ok {
+		return nil, errInvalidPublicKey
+	}
+	return &rsa.PublicKey{N: n, E: 0x10001}, nil
+}
+
+// Encrypt returns a copy of the request with all of its Params replaced by a
+// single encrypted parameter. The Params are AES encrypted using a random
+// passphrase, which in turn is RSA encrypted using the server's public key.
+func (e *EncryptionInfoResponse) Encrypt(r *Request) (*Request, error) {
+	pub, err := e.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var raw [32]byte
+	if _, err := io.ReadFull(rand.Reader, raw[:]); err != nil {
+		return nil, err
+	}
+	passphrase := []byte(base64.RawURLEncoding.EncodeToString(raw[:]))
+
+	plain := url.Values{}
+	for k, l := range r.Params {
+		plain[k] = l
+	}
+	plain.Set(e.CipherToken, strconv.FormatInt(e.ServerTime, 10))
+
+	encPassphrase, err := rsa.EncryptPKCS1v15(rand.Reader, pub, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	encParams, err := opensslEncrypt(passphrase, []byte(plain.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	text, err := json.Marshal(map[string]string{
+		"rsa": base64.StdEncoding.EncodeToString(encPassphrase),
+		"aes": base64.StdEncoding.EncodeToString(encParams),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enc := *r
+	enc.Params = url.Values{e.CipherKey: []string{string(text)}}
+	return &enc, nil
+}
+
+// opensslEncrypt encrypts data with AES-256-CBC in the salted format produced
+// by "openssl enc" and CryptoJS, deriving the key and IV from the passphrase.
+func opensslEncrypt(passphrase, data []byte) ([]byte, error) {
+	var salt [8]byte
+	if _, err := io.ReadFull(rand.Reader, salt[:]); err != nil {
+		return nil, err
+	}
+	key, iv := evpBytesToKey(passphrase, salt[:], 32, aes.BlockSize)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	pad := aes.BlockSize - len(data)%aes.BlockSize
+	data = append(data, bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	out := make([]byte, 16+len(data))
+	copy(out, "Salted__")
+	copy(out[8:], salt[:])
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[16:], data)
+	return out, nil
+}
+
+// evpBytesToKey implements the OpenSSL EVP_BytesToKey key derivation using MD5
+// and a single iteration.
+func evpBytesToKey(passphrase, salt []byte, keyLen, ivLen int) ([]byte, []byte) {
+	var derived, prev []byte
+	for len(derived) < keyLen+ivLen {
+		h := md5.New()
+		h.Write(prev)
+		h.Write(passphrase)
+		h.Write(salt)
+		prev = h.Sum(nil)
+		derived = append(derived, prev...)
+	}
+	return derived[:keyLen], derived[keyLen : keyLen+ivLen]
+}
+
+// EncryptedRequest sends the wrapped request with its parameters encrypted
+// using the given EncryptionInfoResponse.
+type EncryptedRequest struct {
+	Request MarshalRequest
+	Info    *EncryptionInfoResponse
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (e EncryptedRequest) MarshalRequest() (*Request, error) {
+	r, err := e.Request.MarshalRequest()
+	if err != nil {
+		return nil, err
+	}
+	return e.Info.Encrypt(r)
+}
+
+// Validate validates the wrapped request if it implements Validator.
+func (e EncryptedRequest) Validate() error {
+	return validate(e.Request)
+}
+
+// ClientEncryptedLogin is like ClientLogin, except the credentials are sent
+// encrypted using the server's public key instead of as plain parameters.
+// This protects the password on installations only reachable over HTTP.
+// As with ClientLogin, a session loaded from a SessionStore is used instead.
+func ClientEncryptedLogin(l AuthLogin) ClientOption {
+	return func(c *Client) error {
+		if c.restored {
+			return nil
+		}
+		ctx := context.Background()
+		var info EncryptionInfoResponse
+		if err := c.Call(ctx, EncryptionInfo{}, &info); err != nil {
+			return err
+		}
+		var res AuthLoginResponse
+		l.Session = c.loginSession(l.Session)
+		l.Format = "sid"
+		err := c.Call(ctx, EncryptedRequest{Request: l, Info: &info}, &res)
+		if err != nil {
+			return err
+		}
+		return c.storeLogin(l.Session, true, &res)
+	}
+}
@@ -0,0 +1,172 @@
+package syno
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func testEncryptionInfo(t testing.TB) (*rsa.PrivateKey, *EncryptionInfoResponse) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	ensure.Nil(t, err)
+	return key, &EncryptionInfoResponse{
+		CipherKey:   "__cIpHeRtExT",
+		CipherToken: "__cIpHeRtOkEn",
+		PublicKey:   key.N.Text(16),
+		ServerTime:  42,
+	}
+}
+
+func opensslDecrypt(t testing.TB, passphrase, data []byte) []byte {
+	ensure.DeepEqual(t, string(data[:8]), "Salted__")
+	key, iv := evpBytesToKey(passphrase, data[8:16], 32, aes.BlockSize)
+	block, err := aes.NewCipher(key)
+	ensure.Nil(t, err)
+	out := make([]byte, len(data)-16)
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data[16:])
+	return out[:len(out)-int(out[len(out)-1])]
+}
+
+func decryptParams(t testing.TB, key *rsa.PrivateKey, text string) url.Values {
+	var parts map[string]string
+	ensure.Nil(t, json.Unmarshal([]byte(text), &parts))
+	encPassphrase, err := base64.StdEncoding.DecodeString(parts["rsa"])
+	ensure.Nil(t, err)
+	passphrase, err := rsa.DecryptPKCS1v15(nil, key, encPassphrase)
+	ensure.Nil(t, err)
+	encParams, err := base64.StdEncoding.DecodeString(parts["aes"])
+	ensure.Nil(t, err)
+	v, err := url.ParseQuery(string(opensslDecrypt(t, passphrase, encParams)))
+	ensure.Nil(t, err)
+	return v
+}
+
+func TestEncryptionInfoMarshal(t *testing.T) {
+	r, err := EncryptionInfo{}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    encryptionPath,
+		API:     encryptionAPI,
+		Version: encryptionVersion,
+		Method:  "getinfo",
+		Params:  url.Values{"format": []string{"module"}},
+	})
+}
+
+func TestEvpBytesToKey(t *testing.T) {
+	// openssl enc -aes-256-cbc -k pass -S 0102030405060708 -P -md md5
+	key, iv := evpBytesToKey([]byte("pass"), []byte{1, 2, 3, 4, 5, 6, 7, 8}, 32, 16)
+	ensure.DeepEqual(
+		t,
+		hex.EncodeToString(key),
+		"86a00079beb5dca79870668bb0c56a7eb158e10ac9a1ec44cd2fd6ccf7796367",
+	)
+	ensure.DeepEqual(t, hex.EncodeToString(iv), "717e513cb7804e1856cd14dace7097cf")
+}
+
+func TestEncryptRequest(t *testing.T) {
+	key, info := testEncryptionInfo(t)
+	r, err := EncryptedRequest{
+		Request: AuthLogin{Account: "a", Password: "b"},
+		Info:    info,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.API, authLoginAPI)
+	ensure.DeepEqual(t, r.Method, "login")
+	ensure.DeepEqual(t, len(r.Params), 1)
+	ensure.DeepEqual(t, decryptParams(t, key, r.Params.Get(info.CipherKey)), url.Values{
+		"account":       []string{"a"},
+		"passwd":        []string{"b"},
+		"__cIpHeRtOkEn": []string{"42"},
+	})
+}
+
+func TestEncryptInvalidPublicKey(t *testing.T) {
+	info := &EncryptionInfoResponse{PublicKey: "xyz"}
+	_, err := info.Encrypt(&Request{})
+	ensure.DeepEqual(t, err, errInvalidPublicKey)
+}
+
+func TestEncryptedRequestMarshalError(t *testing.T) {
+	givenErr := errors.New("")
+	_, err := EncryptedRequest{
+		Request: funcMarshalRequest(func() (*Request, error) { return nil, givenErr }),
+	}.MarshalRequest()
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientEncryptedLogin(t *testing.T) {
+	key, info := testEncryptionInfo(t)
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			if v.Get("api") == encryptionAPI {
+				return &http.Response{
+					Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+						"success": true,
+						"data":    info,
+					})),
+				}, nil
+			}
+			ensure.DeepEqual(t, v.Get("passwd"), "")
+			ensure.Subset(t, decryptParams(t, key, v.Get(info.CipherKey)), url.Values{
+				"account": []string{"a"},
+				"passwd":  []string{"b"},
+				"format":  []string{"sid"},
+			})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    map[string]string{"sid": "sid"},
+				})),
+			}, nil
+		})),
+		ClientEncryptedLogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.sid, "sid")
+}
+
+func TestClientEncryptedLoginInfoError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, givenErr
+		})),
+		ClientEncryptedLogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.True(t, c == nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientEncryptedLoginError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    &EncryptionInfoResponse{PublicKey: "xyz"},
+				})),
+			}, nil
+		})),
+		ClientEncryptedLogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.True(t, c == nil)
+	ensure.DeepEqual(t, err, errInvalidPublicKey)
+}
@@ -0,0 +1,439 @@
+package syno
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// SortDirection is the order list results are sorted in.
+type SortDirection string
+
+const (
+	SortAscending  = SortDirection("asc")
+	SortDescending = SortDirection("desc")
+)
+
+func (d SortDirection) String() string { return string(d) }
+
+// Valid reports if the direction is one of the known values.
+func (d SortDirection) Valid() bool {
+	switch d {
+	case SortAscending, SortDescending:
+		return true
+	}
+	return false
+}
+
+// FileSortBy is the field FileStation list results are sorted by.
+type FileSortBy string
+
+const (
+	FileSortName       = FileSortBy("name")
+	FileSortSize       = FileSortBy("size")
+	FileSortUser       = FileSortBy("user")
+	FileSortGroup      = FileSortBy("group")
+	FileSortModified   = FileSortBy("mtime")
+	FileSortAccessed   = FileSortBy("atime")
+	FileSortChanged    = FileSortBy("ctime")
+	FileSortCreated    = FileSortBy("crtime")
+	FileSortPermission = FileSortBy("posix")
+	FileSortType       = FileSortBy("type")
+)
+
+func (s FileSortBy) String() string { return string(s) }
+
+// Valid reports if the field is one of the known values.
+func (s FileSortBy) Valid() bool {
+	switch s {
+	case FileSortName, FileSortSize, FileSortUser, FileSortGroup,
+		FileSortModified, FileSortAccessed, FileSortChanged, FileSortCreated,
+		FileSortPermission, FileSortType:
+		return true
+	}
+	return false
+}
+
+// FileType filters FileStation results by the type of entry.
+type FileType string
+
+const (
+	FileTypeFile = FileType("file")
+	FileTypeDir  = FileType("dir")
+	FileTypeAll  = FileType("all")
+)
+
+func (t FileType) String() string { return string(t) }
+
+// Valid reports if the type is one of the known values.
+func (t FileType) Valid() bool {
+	switch t {
+	case FileTypeFile, FileTypeDir, FileTypeAll:
+		return true
+	}
+	return false
+}
+
+// FileOverwriteMode is how FileStation handles files that already exist.
+type FileOverwriteMode string
+
+const (
+	// FileOverwriteError fails with ErrorFileExists if the file exists.
+	FileOverwriteError = FileOverwriteMode("")
+	FileOverwrite      = FileOverwriteMode("overwrite")
+	FileOverwriteSkip  = FileOverwriteMode("skip")
+)
+
+func (m FileOverwriteMode) String() string { return string(m) }
+
+// Valid reports if the mode is one of the known values.
+func (m FileOverwriteMode) Valid() bool {
+	switch m {
+	case FileOverwriteError, FileOverwrite, FileOverwriteSkip:
+		return true
+	}
+	return false
+}
+
+// VirtualFolderType is the type of remote folders mounted through File
+// Station.
+type VirtualFolderType string
+
+const (
+	VirtualFolderCIFS = VirtualFolderType("cifs")
+	VirtualFolderNFS  = VirtualFolderType("nfs")
+	VirtualFolderISO  = VirtualFolderType("iso")
+)
+
+func (t VirtualFolderType) String() string { return string(t) }
+
+// Valid reports if the type is one of the known values.
+func (t VirtualFolderType) Valid() bool {
+	switch t {
+	case VirtualFolderCIFS, VirtualFolderNFS, VirtualFolderISO:
+		return true
+	}
+	return false
+}
+
+// DownloadTaskStatus is the state of a DownloadTask.
+type DownloadTaskStatus string
+
+const (
+	DownloadTaskWaiting            = DownloadTaskStatus("waiting")
+	DownloadTaskDownloading        = DownloadTaskStatus("downloading")
+	DownloadTaskPaused             = DownloadTaskStatus("paused")
+	DownloadTaskFinishing          = DownloadTaskStatus("finishing")
+	DownloadTaskFinished           = DownloadTaskStatus("finished")
+	DownloadTaskHashChecking       = DownloadTaskStatus("hash_checking")
+	DownloadTaskSeeding            = DownloadTaskStatus("seeding")
+	DownloadTaskFilehostingWaiting = DownloadTaskStatus("filehosting_waiting")
+	DownloadTaskExtracting         = DownloadTaskStatus("extracting")
+	DownloadTaskError              = DownloadTaskStatus("error")
+)
+
+func (s DownloadTaskStatus) String() string { return string(s) }
+
+// Done reports if the task has stopped downloading, because it finished,
+// is seeding a finished download or failed.
+func (s DownloadTaskStatus) Done() bool {
+	switch s {
+	case DownloadTaskFinished, DownloadTaskSeeding, DownloadTaskError:
+		return true
+	}
+	return false
+}
+
+// downloadTask2Statuses maps the numeric statuses used by the
+// SYNO.DownloadStation2 APIs to the named ones. Codes of 100 and above are
+// errors.
+var downloadTask2Statuses = map[int]DownloadTaskStatus{
+	1:  DownloadTaskWaiting,
+	2:  DownloadTaskDownloading,
+	3:  DownloadTaskPaused,
+	4:  DownloadTaskFinishing,
+	5:  DownloadTaskFinished,
+	6:  DownloadTaskHashChecking,
+	8:  DownloadTaskSeeding,
+	9:  DownloadTaskFilehostingWaiting,
+	10: DownloadTaskExtracting,
+}
+
+// UnmarshalJSON accepts both the named statuses and the numeric ones used by
+// the SYNO.DownloadStation2 APIs. Unknown numeric statuses are kept as their
+// number.
+func (s *DownloadTaskStatus) UnmarshalJSON(b []byte) error {
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		var str string
+		if err := json.Unmarshal(b, &str); err != nil {
+			return err
+		}
+		*s = DownloadTaskStatus(str)
+		return nil
+	}
+	if status, ok := downloadTask2Statuses[n]; ok {
+		*s = status
+	} else if n >= 100 {
+		*s = DownloadTaskError
+	} else {
+		*s = DownloadTaskStatus(strconv.Itoa(n))
+	}
+	return nil
+}
+
+// Valid reports if the status is one of the known values.
+func (s DownloadTaskStatus) Valid() bool {
+	switch s {
+	case DownloadTaskWaiting, DownloadTaskDownloading, DownloadTaskPaused,
+		DownloadTaskFinishing, DownloadTaskFinished, DownloadTaskHashChecking,
+		DownloadTaskSeeding, DownloadTaskFilehostingWaiting,
+		DownloadTaskExtracting, DownloadTaskError:
+		return true
+	}
+	return false
+}
+
+// downloadTask2StatusCode returns the numeric status used by the
+// SYNO.DownloadStation2 APIs for the named one. It reports false for
+// DownloadTaskError, which covers a range of codes, and unknown statuses.
+func downloadTask2StatusCode(s DownloadTaskStatus) (int, bool) {
+	for code, status := range downloadTask2Statuses {
+		if status == s {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// DownloadTaskSortBy is the field download task list results are sorted by.
+type DownloadTaskSortBy string
+
+const (
+	DownloadTaskSortCreated     = DownloadTaskSortBy("created_time")
+	DownloadTaskSortTitle       = DownloadTaskSortBy("title")
+	DownloadTaskSortSize        = DownloadTaskSortBy("size")
+	DownloadTaskSortStatus      = DownloadTaskSortBy("status")
+	DownloadTaskSortType        = DownloadTaskSortBy("type")
+	DownloadTaskSortDestination = DownloadTaskSortBy("destination")
+)
+
+func (s DownloadTaskSortBy) String() string { return string(s) }
+
+// Valid reports if the field is one of the known values.
+func (s DownloadTaskSortBy) Valid() bool {
+	switch s {
+	case DownloadTaskSortCreated, DownloadTaskSortTitle, DownloadTaskSortSize,
+		DownloadTaskSortStatus, DownloadTaskSortType, DownloadTaskSortDestination:
+		return true
+	}
+	return false
+}
+
+// DownloadTaskFilter selects download tasks by their status and type. An empty
+// list matches every status or type.
+type DownloadTaskFilter struct {
+	Status []DownloadTaskStatus
+	Type   []DownloadTaskType
+}
+
+// Match reports if the task is selected by the filter.
+func (f DownloadTaskFilter) Match(t DownloadTask) bool {
+	return (len(f.Status) == 0 || containsStatus(f.Status, t.Status)) &&
+		(len(f.Type) == 0 || containsType(f.Type, t.Type))
+}
+
+func containsStatus(l []DownloadTaskStatus, s DownloadTaskStatus) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(l []DownloadTaskType, t DownloadTaskType) bool {
+	for _, v := range l {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DownloadTaskType is the protocol a DownloadTask downloads with.
+type DownloadTaskType string
+
+const (
+	DownloadTaskBT    = DownloadTaskType("bt")
+	DownloadTaskNZB   = DownloadTaskType("nzb")
+	DownloadTaskHTTP  = DownloadTaskType("http")
+	DownloadTaskFTP   = DownloadTaskType("ftp")
+	DownloadTaskEMule = DownloadTaskType("emule")
+)
+
+func (t DownloadTaskType) String() string { return string(t) }
+
+// Valid reports if the type is one of the known values.
+func (t DownloadTaskType) Valid() bool {
+	switch t {
+	case DownloadTaskBT, DownloadTaskNZB, DownloadTaskHTTP, DownloadTaskFTP,
+		DownloadTaskEMule:
+		return true
+	}
+	return false
+}
+
+// SurveillanceProfile is the stream profile of a camera, which sets the
+// resolution and quality of its video and snapshots.
+type SurveillanceProfile int
+
+const (
+	SurveillanceProfileHigh     = SurveillanceProfile(0)
+	SurveillanceProfileBalanced = SurveillanceProfile(1)
+	SurveillanceProfileLow      = SurveillanceProfile(2)
+)
+
+func (p SurveillanceProfile) String() string {
+	switch p {
+	case SurveillanceProfileHigh:
+		return "high"
+	case SurveillanceProfileBalanced:
+		return "balanced"
+	case SurveillanceProfileLow:
+		return "low"
+	}
+	return strconv.Itoa(int(p))
+}
+
+// Valid reports if the profile is one of the known values.
+func (p SurveillanceProfile) Valid() bool {
+	switch p {
+	case SurveillanceProfileHigh, SurveillanceProfileBalanced,
+		SurveillanceProfileLow:
+		return true
+	}
+	return false
+}
+
+// SurveillanceRecordingReason is what triggered a recording.
+type SurveillanceRecordingReason int
+
+const (
+	SurveillanceRecordingContinuous = SurveillanceRecordingReason(1)
+	SurveillanceRecordingMotion     = SurveillanceRecordingReason(2)
+	SurveillanceRecordingAlarm      = SurveillanceRecordingReason(3)
+	SurveillanceRecordingActionRule = SurveillanceRecordingReason(4)
+	SurveillanceRecordingManual     = SurveillanceRecordingReason(5)
+	SurveillanceRecordingExternal   = SurveillanceRecordingReason(6)
+)
+
+func (r SurveillanceRecordingReason) String() string {
+	switch r {
+	case SurveillanceRecordingContinuous:
+		return "continuous"
+	case SurveillanceRecordingMotion:
+		return "motion"
+	case SurveillanceRecordingAlarm:
+		return "alarm"
+	case SurveillanceRecordingActionRule:
+		return "action_rule"
+	case SurveillanceRecordingManual:
+		return "manual"
+	case SurveillanceRecordingExternal:
+		return "external"
+	}
+	return strconv.Itoa(int(r))
+}
+
+// Valid reports if the reason is one of the known values.
+func (r SurveillanceRecordingReason) Valid() bool {
+	return r >= SurveillanceRecordingContinuous && r <= SurveillanceRecordingExternal
+}
+
+// SurveillancePTZDirection is the direction a PTZ camera moves in.
+type SurveillancePTZDirection string
+
+const (
+	SurveillancePTZUp    = SurveillancePTZDirection("up")
+	SurveillancePTZDown  = SurveillancePTZDirection("down")
+	SurveillancePTZLeft  = SurveillancePTZDirection("left")
+	SurveillancePTZRight = SurveillancePTZDirection("right")
+	SurveillancePTZHome  = SurveillancePTZDirection("home")
+)
+
+func (d SurveillancePTZDirection) String() string { return string(d) }
+
+// Valid reports if the direction is one of the known values.
+func (d SurveillancePTZDirection) Valid() bool {
+	switch d {
+	case SurveillancePTZUp, SurveillancePTZDown, SurveillancePTZLeft,
+		SurveillancePTZRight, SurveillancePTZHome:
+		return true
+	}
+	return false
+}
+
+// SurveillancePTZZoomControl is the direction a PTZ camera zooms in.
+type SurveillancePTZZoomControl string
+
+const (
+	SurveillancePTZZoomIn  = SurveillancePTZZoomControl("in")
+	SurveillancePTZZoomOut = SurveillancePTZZoomControl("out")
+)
+
+func (z SurveillancePTZZoomControl) String() string { return string(z) }
+
+// Valid reports if the zoom is one of the known values.
+func (z SurveillancePTZZoomControl) Valid() bool {
+	return z == SurveillancePTZZoomIn || z == SurveillancePTZZoomOut
+}
+
+// SurveillancePTZMoveType is how far a PTZ camera moves or zooms.
+type SurveillancePTZMoveType string
+
+const (
+	// SurveillancePTZStep moves by a single step.
+	SurveillancePTZStep = SurveillancePTZMoveType("")
+
+	// SurveillancePTZStart moves continuously until a request with
+	// SurveillancePTZStop.
+	SurveillancePTZStart = SurveillancePTZMoveType("Start")
+	SurveillancePTZStop  = SurveillancePTZMoveType("Stop")
+)
+
+func (t SurveillancePTZMoveType) String() string { return string(t) }
+
+// Valid reports if the move type is one of the known values.
+func (t SurveillancePTZMoveType) Valid() bool {
+	switch t {
+	case SurveillancePTZStep, SurveillancePTZStart, SurveillancePTZStop:
+		return true
+	}
+	return false
+}
+
+// SurveillanceMotionSource is what detects motion for a camera.
+type SurveillanceMotionSource int
+
+const (
+	SurveillanceMotionDisabled = SurveillanceMotionSource(-1)
+	SurveillanceMotionByCamera = SurveillanceMotionSource(0)
+	SurveillanceMotionByServer = SurveillanceMotionSource(1)
+)
+
+func (s SurveillanceMotionSource) String() string {
+	switch s {
+	case SurveillanceMotionDisabled:
+		return "disabled"
+	case SurveillanceMotionByCamera:
+		return "camera"
+	case SurveillanceMotionByServer:
+		return "server"
+	}
+	return strconv.Itoa(int(s))
+}
+
+// Valid reports if the source is one of the known values.
+func (s SurveillanceMotionSource) Valid() bool {
+	return s >= SurveillanceMotionDisabled && s <= SurveillanceMotionByServer
+}
@@ -0,0 +1,83 @@
+package syno
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestEnumsValid(t *testing.T) {
+	cases := []struct {
+		Value interface {
+			String() string
+			Valid() bool
+		}
+		Valid bool
+	}{
+		{Value: SortDescending, Valid: true},
+		{Value: SortDirection("up"), Valid: false},
+		{Value: FileSortCreated, Valid: true},
+		{Value: FileSortBy("Name"), Valid: false},
+		{Value: FileTypeDir, Valid: true},
+		{Value: FileType(""), Valid: false},
+		{Value: VirtualFolderISO, Valid: true},
+		{Value: VirtualFolderType("smb"), Valid: false},
+		{Value: FileOverwriteSkip, Valid: true},
+		{Value: FileOverwriteMode("always"), Valid: false},
+		{Value: DownloadTaskHashChecking, Valid: true},
+		{Value: DownloadTaskStatus("done"), Valid: false},
+		{Value: DownloadTaskBT, Valid: true},
+		{Value: DownloadTaskType("torrent"), Valid: false},
+		{Value: DownloadTaskSortCreated, Valid: true},
+		{Value: DownloadTaskSortBy("name"), Valid: false},
+		{Value: SurveillanceProfileLow, Valid: true},
+		{Value: SurveillanceProfile(3), Valid: false},
+		{Value: SurveillanceRecordingMotion, Valid: true},
+		{Value: SurveillanceRecordingReason(0), Valid: false},
+		{Value: SurveillancePTZHome, Valid: true},
+		{Value: SurveillancePTZDirection("north"), Valid: false},
+		{Value: SurveillancePTZZoomOut, Valid: true},
+		{Value: SurveillancePTZZoomControl(""), Valid: false},
+		{Value: SurveillancePTZStart, Valid: true},
+		{Value: SurveillancePTZMoveType("start"), Valid: false},
+		{Value: SurveillanceMotionDisabled, Valid: true},
+		{Value: SurveillanceMotionSource(2), Valid: false},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, c.Value.Valid(), c.Valid, c.Value.String())
+	}
+}
+
+func TestEnumsString(t *testing.T) {
+	ensure.DeepEqual(t, SortAscending.String(), "asc")
+	ensure.DeepEqual(t, FileSortModified.String(), "mtime")
+	ensure.DeepEqual(t, FileTypeAll.String(), "all")
+	ensure.DeepEqual(t, DownloadTaskSeeding.String(), "seeding")
+	ensure.DeepEqual(t, DownloadTaskHTTP.String(), "http")
+	ensure.DeepEqual(t, SurveillanceProfileBalanced.String(), "balanced")
+	ensure.DeepEqual(t, SurveillanceProfile(3).String(), "3")
+	ensure.DeepEqual(t, SurveillanceRecordingActionRule.String(), "action_rule")
+}
+
+func TestDownloadTaskStatusDone(t *testing.T) {
+	ensure.True(t, DownloadTaskFinished.Done())
+	ensure.True(t, DownloadTaskSeeding.Done())
+	ensure.True(t, DownloadTaskError.Done())
+	ensure.False(t, DownloadTaskDownloading.Done())
+	ensure.False(t, DownloadTaskPaused.Done())
+}
+
+func TestDownloadTaskFilterMatch(t *testing.T) {
+	task := DownloadTask{Type: DownloadTaskHTTP, Status: DownloadTaskPaused}
+	ensure.True(t, DownloadTaskFilter{}.Match(task))
+	ensure.True(t, DownloadTaskFilter{
+		Status: []DownloadTaskStatus{DownloadTaskWaiting, DownloadTaskPaused},
+	}.Match(task))
+	ensure.False(t, DownloadTaskFilter{
+		Status: []DownloadTaskStatus{DownloadTaskWaiting},
+	}.Match(task))
+	ensure.False(t, DownloadTaskFilter{
+		Status: []DownloadTaskStatus{DownloadTaskPaused},
+		Type:   []DownloadTaskType{DownloadTaskBT},
+	}.Match(task))
+}
@@ -0,0 +1,143 @@
+package syno
+
+import "net/http"
+
+// The ACL APIs are not part of the published FileStation API. Their
+// parameters are those sent by the properties dialog of File Station.
+const (
+	fileACLAPI     = "SYNO.Core.ACL"
+	fileACLVersion = "1"
+
+	fileOwnerAPI     = "SYNO.FileStation.Property.ACLOwner"
+	fileOwnerVersion = "1"
+)
+
+// FileStationGetACL gets the ACL of a file or folder. The response is
+// FileACLResponse.
+type FileStationGetACL struct {
+	Path string `syno:"file_path"`
+}
+
+// Validate checks that the path is given.
+func (f FileStationGetACL) Validate() error {
+	return requireField("Path", f.Path)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationGetACL) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileACLAPI, fileACLVersion, "get", f)
+}
+
+// FileACLResponse is the response for FileStationGetACL. Inherited reports if
+// the rules of the parent folder also apply, and those rules are included
+// with their Inherited set.
+type FileACLResponse struct {
+	Path      string        `json:"file_path"`
+	Owner     string        `json:"owner"`
+	IsACLMode bool          `json:"is_acl_mode"`
+	Inherited bool          `json:"inherited"`
+	Rules     []FileACLRule `json:"rules"`
+}
+
+// FileACLRule allows or denies permissions to a user or group. OwnerType is
+// "user", "group", "everyone" or "owner", and PermissionType is "allow" or
+// "deny".
+type FileACLRule struct {
+	OwnerType      string            `json:"owner_type"`
+	OwnerName      string            `json:"owner_name,omitempty"`
+	PermissionType string            `json:"permission_type"`
+	Permission     FileACLPermission `json:"permission"`
+	Inherit        FileACLInherit    `json:"inherit"`
+
+	// Inherited is set for rules coming from a parent folder, which cannot
+	// be changed on the file itself.
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// FileACLPermission are the permissions granted or denied by a FileACLRule.
+type FileACLPermission struct {
+	ReadData           bool `json:"read_data"`
+	WriteData          bool `json:"write_data"`
+	ExecuteFile        bool `json:"exe_file"`
+	AppendData         bool `json:"append_data"`
+	Delete             bool `json:"delete"`
+	DeleteSub          bool `json:"delete_sub"`
+	ReadAttributes     bool `json:"read_attr"`
+	WriteAttributes    bool `json:"write_attr"`
+	ReadExtAttributes  bool `json:"read_ext_attr"`
+	WriteExtAttributes bool `json:"write_ext_attr"`
+	ReadPermissions    bool `json:"read_perm"`
+	ChangePermissions  bool `json:"change_perm"`
+	TakeOwnership      bool `json:"take_ownership"`
+}
+
+// FileACLInherit is what a FileACLRule set on a folder applies to.
+type FileACLInherit struct {
+	ThisFolder     bool `json:"this_folder"`
+	ChildFiles     bool `json:"child_files"`
+	ChildFolders   bool `json:"child_folders"`
+	AllDescendants bool `json:"all_descendants"`
+}
+
+// FileStationSetACL replaces the ACL rules of a file or folder. Inherited
+// rules in Rules are ignored. It does not have a response.
+type FileStationSetACL struct {
+	Path  string        `syno:"file_path"`
+	Rules []FileACLRule `syno:"rules,json"`
+
+	// Inherited applies the rules of the parent folder in addition to Rules.
+	Inherited bool `syno:"inherited"`
+
+	// ApplyToChildren replaces the rules of everything in the folder too.
+	ApplyToChildren bool `syno:"apply_to_children,omitempty"`
+}
+
+// Validate checks that the path is given.
+func (f FileStationSetACL) Validate() error {
+	return requireField("Path", f.Path)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationSetACL) MarshalRequest() (*Request, error) {
+	rules := make([]FileACLRule, 0, len(f.Rules))
+	for _, r := range f.Rules {
+		if !r.Inherited {
+			rules = append(rules, r)
+		}
+	}
+	f.Rules = rules
+	r, err := fileStationRequest(fileACLAPI, fileACLVersion, "set", f)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+// FileStationSetOwner changes the user owning a file or folder. It does not
+// have a response.
+type FileStationSetOwner struct {
+	Path  string `syno:"file_path"`
+	Owner string `syno:"owner"`
+
+	// ApplyToChildren changes the owner of everything in the folder too.
+	ApplyToChildren bool `syno:"apply_to_children,omitempty"`
+}
+
+// Validate checks that the path and owner are given.
+func (f FileStationSetOwner) Validate() error {
+	if err := requireField("Path", f.Path); err != nil {
+		return err
+	}
+	return requireField("Owner", f.Owner)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationSetOwner) MarshalRequest() (*Request, error) {
+	r, err := fileStationRequest(fileOwnerAPI, fileOwnerVersion, "set", f)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
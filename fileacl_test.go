@@ -0,0 +1,98 @@
+package syno
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestFileStationGetACLMarshal(t *testing.T) {
+	r, err := FileStationGetACL{Path: "/video/a"}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileACLAPI,
+		Version: fileACLVersion,
+		Method:  "get",
+		Params:  url.Values{"file_path": []string{"/video/a"}},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFileACLResponseUnmarshal(t *testing.T) {
+	var res FileACLResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"file_path": "/video/a",
+		"owner": "admin",
+		"is_acl_mode": true,
+		"inherited": true,
+		"rules": [{
+			"owner_type": "group",
+			"owner_name": "users",
+			"permission_type": "allow",
+			"permission": {"read_data": true, "read_attr": true},
+			"inherit": {"child_files": true, "child_folders": true},
+			"inherited": true
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, FileACLResponse{
+		Path:      "/video/a",
+		Owner:     "admin",
+		IsACLMode: true,
+		Inherited: true,
+		Rules: []FileACLRule{{
+			OwnerType:      "group",
+			OwnerName:      "users",
+			PermissionType: "allow",
+			Permission:     FileACLPermission{ReadData: true, ReadAttributes: true},
+			Inherit:        FileACLInherit{ChildFiles: true, ChildFolders: true},
+			Inherited:      true,
+		}},
+	})
+}
+
+func TestFileStationSetACLMarshal(t *testing.T) {
+	r, err := FileStationSetACL{
+		Path: "/video/a",
+		Rules: []FileACLRule{
+			{OwnerType: "everyone", PermissionType: "deny", Inherited: true},
+			{
+				OwnerType:      "user",
+				OwnerName:      "guest",
+				PermissionType: "allow",
+				Permission:     FileACLPermission{ReadData: true},
+				Inherit:        FileACLInherit{ThisFolder: true},
+			},
+		},
+		ApplyToChildren: true,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.HTTPMethod, http.MethodPost)
+	ensure.DeepEqual(t, r.Params.Get("file_path"), "/video/a")
+	ensure.DeepEqual(t, r.Params.Get("inherited"), "false")
+	ensure.DeepEqual(t, r.Params.Get("apply_to_children"), "true")
+	var rules []FileACLRule
+	ensure.Nil(t, json.Unmarshal([]byte(r.Params.Get("rules")), &rules))
+	ensure.DeepEqual(t, len(rules), 1)
+	ensure.DeepEqual(t, rules[0].OwnerName, "guest")
+}
+
+func TestFileStationSetOwnerMarshal(t *testing.T) {
+	r, err := FileStationSetOwner{Path: "/video/a", Owner: "guest"}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       fileStationPath,
+		API:        fileOwnerAPI,
+		Version:    fileOwnerVersion,
+		Method:     "set",
+		HTTPMethod: http.MethodPost,
+		Params: url.Values{
+			"file_path": []string{"/video/a"},
+			"owner":     []string{"guest"},
+		},
+		Session: SessionFileStation,
+	})
+}
@@ -0,0 +1,106 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestFileStationExtractStartMarshal(t *testing.T) {
+	r, err := FileStationExtractStart{
+		FilePath:   "/video/a.zip",
+		DestFolder: "/video",
+		Flatten:    true,
+		Password:   "secret",
+		ItemIDs:    []int{1, 3},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileExtractAPI,
+		Version: fileExtractVersion,
+		Method:  "start",
+		Params: url.Values{
+			"file_path":        []string{"/video/a.zip"},
+			"dest_folder_path": []string{"/video"},
+			"overwrite":        []string{"false"},
+			"keep_dir":         []string{"false"},
+			"password":         []string{"secret"},
+			"item_id":          []string{"1,3"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFileStationExtractListMarshal(t *testing.T) {
+	r, err := FileStationExtractList{
+		FilePath: "/video/a.zip",
+		Limit:    10,
+		SortBy:   FileSortSize,
+		ItemID:   2,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "list")
+	ensure.DeepEqual(t, r.Params, url.Values{
+		"file_path": []string{"/video/a.zip"},
+		"limit":     []string{"10"},
+		"sort_by":   []string{"size"},
+		"item_id":   []string{"2"},
+	})
+}
+
+func TestArchiveItemListResponseUnmarshal(t *testing.T) {
+	var res ArchiveItemListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"total": 1,
+		"items": [{
+			"itemid": 1,
+			"name": "a.mkv",
+			"path": "a.mkv",
+			"is_dir": false,
+			"size": 2048,
+			"pack_size": 1024,
+			"mtime": "2013-02-21 17:09:40"
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, ArchiveItemListResponse{
+		Total: 1,
+		Items: []ArchiveItem{{
+			ID:       1,
+			Name:     "a.mkv",
+			Path:     "a.mkv",
+			Size:     2048,
+			PackSize: 1024,
+			Modified: "2013-02-21 17:09:40",
+		}},
+	})
+}
+
+func TestClientExtract(t *testing.T) {
+	var methods []string
+	var statuses int
+	c := fileTaskClient(t, func(v url.Values) string {
+		ensure.DeepEqual(t, v.Get("api"), fileExtractAPI)
+		methods = append(methods, v.Get("method"))
+		if v.Get("method") == "start" {
+			return `{"taskid":"t"}`
+		}
+		ensure.DeepEqual(t, v.Get("taskid"), "t")
+		statuses++
+		if statuses == 1 {
+			return `{"finished":false,"progress":0.5}`
+		}
+		return `{"finished":true,"progress":1}`
+	})
+	err := c.Extract(
+		context.Background(),
+		FileStationExtractStart{FilePath: "/a.zip", DestFolder: "/b"},
+		time.Microsecond,
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, methods, []string{"start", "status", "status"})
+}
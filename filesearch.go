@@ -0,0 +1,191 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/daaku/syno/params"
+)
+
+const (
+	fileSearchAPI     = "SYNO.FileStation.Search"
+	fileSearchVersion = "2"
+)
+
+// FileStationSearchStart starts searching for files in the folders. The search
+// runs in the background and its results are fetched with
+// FileStationSearchList. The response is FileSearchStartResponse.
+type FileStationSearchStart struct {
+	FolderPaths []string
+
+	// NotRecursive only searches the folders themselves, not their subfolders.
+	NotRecursive bool
+
+	// Pattern matches the file names using a glob pattern.
+	Pattern string
+
+	// Extension matches the file extension, such as "mkv".
+	Extension string
+
+	FileType FileType
+
+	// SizeFrom and SizeTo are the inclusive bounds on the file size in bytes,
+	// and are ignored if zero.
+	SizeFrom Bytes
+	SizeTo   Bytes
+
+	// ModifiedFrom and ModifiedTo bound the modification time, and are ignored
+	// if zero.
+	ModifiedFrom time.Time
+	ModifiedTo   time.Time
+
+	Owner string
+	Group string
+}
+
+// Validate checks that at least one folder is given.
+func (f FileStationSearchStart) Validate() error {
+	if len(f.FolderPaths) == 0 {
+		return &ValidationError{Field: "FolderPaths", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationSearchStart) MarshalRequest() (*Request, error) {
+	p := url.Values{}
+	params.SetJSONArray(p, "folder_path", f.FolderPaths)
+	if f.NotRecursive {
+		p.Set("recursive", "false")
+	}
+	params.SetString(p, "pattern", f.Pattern)
+	params.SetString(p, "extension", f.Extension)
+	params.SetString(p, "filetype", string(f.FileType))
+	params.SetInt64(p, "size_from", int64(f.SizeFrom))
+	params.SetInt64(p, "size_to", int64(f.SizeTo))
+	if !f.ModifiedFrom.IsZero() {
+		params.SetInt64(p, "mtime_from", f.ModifiedFrom.Unix())
+	}
+	if !f.ModifiedTo.IsZero() {
+		params.SetInt64(p, "mtime_to", f.ModifiedTo.Unix())
+	}
+	params.SetString(p, "owner", f.Owner)
+	params.SetString(p, "group", f.Group)
+	return &Request{
+		Path:    fileStationPath,
+		API:     fileSearchAPI,
+		Version: fileSearchVersion,
+		Method:  "start",
+		Params:  p,
+		Session: SessionFileStation,
+	}, nil
+}
+
+// FileSearchStartResponse is the response for FileStationSearchStart.
+type FileSearchStartResponse struct {
+	TaskID string `json:"taskid"`
+}
+
+// FileStationSearchList lists the files found so far by a search. The
+// response is FileSearchListResponse.
+type FileStationSearchList struct {
+	TaskID        string        `syno:"taskid"`
+	Offset        int           `syno:"offset,omitempty"`
+	Limit         int           `syno:"limit,omitempty"`
+	SortBy        FileSortBy    `syno:"sort_by,omitempty"`
+	SortDirection SortDirection `syno:"sort_direction,omitempty"`
+	Additional    []string      `syno:"additional,omitempty"`
+}
+
+// Validate checks that the search task ID is given.
+func (f FileStationSearchList) Validate() error {
+	return requireField("TaskID", f.TaskID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationSearchList) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileSearchAPI, fileSearchVersion, "list", f)
+}
+
+// FileSearchListResponse is the response for FileStationSearchList. Finished
+// reports if the search has completed, otherwise more files may be returned by
+// listing again.
+type FileSearchListResponse struct {
+	ListResponse[File]
+	Finished bool
+}
+
+// UnmarshalJSON decodes the response.
+func (r *FileSearchListResponse) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Finished bool `json:"finished"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &r.ListResponse); err != nil {
+		return err
+	}
+	r.Finished = v.Finished
+	return nil
+}
+
+// FileStationSearchStop stops searches. Their results can still be listed
+// until they are removed with FileStationSearchClean. It does not have a
+// response.
+type FileStationSearchStop struct {
+	TaskIDs []string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationSearchStop) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileSearchAPI, fileSearchVersion, "stop", f)
+}
+
+// FileStationSearchClean removes the results of searches. It does not have a
+// response.
+type FileStationSearchClean struct {
+	TaskIDs []string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationSearchClean) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileSearchAPI, fileSearchVersion, "clean", f)
+}
+
+// SearchFiles runs the search, polling every interval until it finishes, and
+// returns the files found with the additional information requested. The
+// search is stopped and its results removed before returning, even if the
+// context is done.
+func (c *Client) SearchFiles(
+	ctx context.Context,
+	s FileStationSearchStart,
+	interval time.Duration,
+	additional ...string,
+) ([]File, error) {
+	var start FileSearchStartResponse
+	if err := c.Call(ctx, s, &start); err != nil {
+		return nil, err
+	}
+	ids := []string{start.TaskID}
+	defer c.stopFileTask(FileStationSearchClean{TaskIDs: ids})
+	defer c.stopFileTask(FileStationSearchStop{TaskIDs: ids})
+
+	var files []File
+	err := poll(ctx, interval, func() (bool, error) {
+		var res FileSearchListResponse
+		err := c.Call(ctx, FileStationSearchList{
+			TaskID:     start.TaskID,
+			Limit:      -1,
+			Additional: additional,
+		}, &res)
+		files = res.Items
+		return res.Finished, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
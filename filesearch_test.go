@@ -0,0 +1,116 @@
+package syno
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestFileStationSearchMarshal(t *testing.T) {
+	cases := []struct {
+		Request MarshalRequest
+		Method  string
+		Params  url.Values
+	}{
+		{
+			Request: FileStationSearchStart{
+				FolderPaths:  []string{"/video", "/music"},
+				NotRecursive: true,
+				Pattern:      "a*",
+				Extension:    "mkv",
+				FileType:     FileTypeFile,
+				SizeFrom:     1024,
+				ModifiedFrom: time.Unix(1500000000, 0),
+				Owner:        "admin",
+			},
+			Method: "start",
+			Params: url.Values{
+				"folder_path": []string{`["/video","/music"]`},
+				"recursive":   []string{"false"},
+				"pattern":     []string{"a*"},
+				"extension":   []string{"mkv"},
+				"filetype":    []string{"file"},
+				"size_from":   []string{"1024"},
+				"mtime_from":  []string{"1500000000"},
+				"owner":       []string{"admin"},
+			},
+		},
+		{
+			Request: FileStationSearchList{TaskID: "t", Limit: -1},
+			Method:  "list",
+			Params: url.Values{
+				"taskid": []string{"t"},
+				"limit":  []string{"-1"},
+			},
+		},
+		{
+			Request: FileStationSearchStop{TaskIDs: []string{"t", "u"}},
+			Method:  "stop",
+			Params:  url.Values{"taskid": []string{"t,u"}},
+		},
+		{
+			Request: FileStationSearchClean{TaskIDs: []string{"t"}},
+			Method:  "clean",
+			Params:  url.Values{"taskid": []string{"t"}},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:    fileStationPath,
+			API:     fileSearchAPI,
+			Version: fileSearchVersion,
+			Method:  c.Method,
+			Params:  c.Params,
+			Session: SessionFileStation,
+		})
+	}
+}
+
+func TestClientSearchFiles(t *testing.T) {
+	var methods []string
+	var lists int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.Nil(t, r.ParseForm())
+			methods = append(methods, r.Form.Get("method"))
+			data := "null"
+			switch r.Form.Get("method") {
+			case "start":
+				data = `{"taskid":"t"}`
+			case "list":
+				ensure.DeepEqual(t, r.Form.Get("taskid"), "t")
+				ensure.DeepEqual(t, r.Form.Get("additional"), "size")
+				lists++
+				data = `{"finished":false,"total":0,"offset":0,"files":[]}`
+				if lists > 1 {
+					data = `{"finished":true,"total":1,"offset":0,"files":[{"path":"/a"}]}`
+				}
+			default:
+				ensure.DeepEqual(t, r.Form.Get("taskid"), "t")
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":true,"data":` + data + `}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	files, err := c.SearchFiles(
+		context.Background(),
+		FileStationSearchStart{FolderPaths: []string{"/"}},
+		time.Microsecond,
+		FileAdditionalSize,
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, files, []File{{Path: "/a"}})
+	ensure.DeepEqual(t, methods, []string{"start", "list", "list", "stop", "clean"})
+}
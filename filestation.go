@@ -0,0 +1,594 @@
+package syno
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Errors returned by the SYNO.FileStation APIs. Other APIs use the same codes
+// with different meanings. Batch operations report the codes for individual
+// paths as the Details of a ResponseError.
+const (
+	ErrorFileInvalidParameter      = Error(400)
+	ErrorFileUnknown               = Error(401)
+	ErrorFileSystemBusy            = Error(402)
+	ErrorFileInvalidUser           = Error(403)
+	ErrorFileInvalidGroup          = Error(404)
+	ErrorFileInvalidUserAndGroup   = Error(405)
+	ErrorFileAccountServer         = Error(406)
+	ErrorFileNotPermitted          = Error(407)
+	ErrorFileNotExist              = Error(408)
+	ErrorFileUnsupportedFileSystem = Error(409)
+	ErrorFileRemoteConnect         = Error(410)
+	ErrorFileReadOnly              = Error(411)
+	ErrorFileNameTooLong           = Error(412)
+	ErrorFileEncryptedNameTooLong  = Error(413)
+	ErrorFileExists                = Error(414)
+	ErrorFileQuotaExceeded         = Error(415)
+	ErrorFileNoSpace               = Error(416)
+	ErrorFileIO                    = Error(417)
+	ErrorFileIllegalPath           = Error(418)
+	ErrorFileIllegalName           = Error(419)
+	ErrorFileIllegalFATName        = Error(420)
+	ErrorFileBusy                  = Error(421)
+	ErrorFileNoSuchTask            = Error(599)
+	ErrorFileFavoriteExists        = Error(800)
+	ErrorFileFavoriteNameConflict  = Error(801)
+	ErrorFileTooManyFavorites      = Error(802)
+	ErrorFileDeleteFailed          = Error(900)
+	ErrorFileCopyFailed            = Error(1000)
+	ErrorFileMoveFailed            = Error(1001)
+	ErrorFileDestination           = Error(1002)
+	ErrorFileNoOverwriteMode       = Error(1003)
+	ErrorFileTypeConflict          = Error(1004)
+	ErrorFileFATSpecialCharacters  = Error(1006)
+	ErrorFileFATTooBig             = Error(1007)
+	ErrorFileCreateFolderFailed    = Error(1100)
+	ErrorFileTooManyFolders        = Error(1101)
+	ErrorFileRenameFailed          = Error(1200)
+	ErrorFileCompressFailed        = Error(1300)
+	ErrorFileArchiveNameTooLong    = Error(1301)
+	ErrorFileExtractFailed         = Error(1400)
+	ErrorFileNotArchive            = Error(1401)
+	ErrorFileArchiveRead           = Error(1402)
+	ErrorFileWrongPassword         = Error(1403)
+	ErrorFileArchiveList           = Error(1404)
+	ErrorFileArchiveItemNotFound   = Error(1405)
+	ErrorFileUploadNoLength        = Error(1800)
+	ErrorFileUploadTimeout         = Error(1801)
+	ErrorFileUploadNoName          = Error(1802)
+	ErrorFileUploadCancelled       = Error(1803)
+	ErrorFileUploadFATTooBig       = Error(1804)
+	ErrorFileUploadNoOverwrite     = Error(1805)
+)
+
+func init() {
+	RegisterRetryableErrors("SYNO.FileStation", ErrorFileSystemBusy, ErrorFileBusy)
+	RegisterAPIErrorStrings("SYNO.FileStation", map[Error]string{
+		ErrorFileInvalidParameter:      "invalid parameter of file operation",
+		ErrorFileUnknown:               "unknown error of file operation",
+		ErrorFileSystemBusy:            "system is too busy",
+		ErrorFileInvalidUser:           "invalid user does this file operation",
+		ErrorFileInvalidGroup:          "invalid group does this file operation",
+		ErrorFileInvalidUserAndGroup:   "invalid user and group does this file operation",
+		ErrorFileAccountServer:         "cannot get user/group information from the account server",
+		ErrorFileNotPermitted:          "operation not permitted",
+		ErrorFileNotExist:              "no such file or directory",
+		ErrorFileUnsupportedFileSystem: "non-supported file system",
+		ErrorFileRemoteConnect:         "failed to connect internet-based file system",
+		ErrorFileReadOnly:              "read-only file system",
+		ErrorFileNameTooLong:           "filename too long",
+		ErrorFileEncryptedNameTooLong:  "filename too long in the encrypted file system",
+		ErrorFileExists:                "file already exists",
+		ErrorFileQuotaExceeded:         "disk quota exceeded",
+		ErrorFileNoSpace:               "no space left on device",
+		ErrorFileIO:                    "input/output error",
+		ErrorFileIllegalPath:           "illegal name or path",
+		ErrorFileIllegalName:           "illegal file name",
+		ErrorFileIllegalFATName:        "illegal file name on FAT file system",
+		ErrorFileBusy:                  "device or resource busy",
+		ErrorFileNoSuchTask:            "no such task of the file operation",
+		ErrorFileFavoriteExists:        "favorite folder is already added",
+		ErrorFileFavoriteNameConflict:  "favorite folder name conflicts",
+		ErrorFileTooManyFavorites:      "too many favorites",
+		ErrorFileDeleteFailed:          "failed to delete files or folders",
+		ErrorFileCopyFailed:            "failed to copy files or folders",
+		ErrorFileMoveFailed:            "failed to move files or folders",
+		ErrorFileDestination:           "an error occurred at the destination",
+		ErrorFileNoOverwriteMode:       "cannot overwrite or skip the existing file without an overwrite mode",
+		ErrorFileTypeConflict:          "cannot overwrite a file with a folder or a folder with a file",
+		ErrorFileFATSpecialCharacters:  "cannot copy or move special characters to a FAT32 file system",
+		ErrorFileFATTooBig:             "cannot copy or move a file bigger than 4G to a FAT32 file system",
+		ErrorFileCreateFolderFailed:    "failed to create a folder",
+		ErrorFileTooManyFolders:        "too many folders in the parent folder",
+		ErrorFileRenameFailed:          "failed to rename",
+		ErrorFileCompressFailed:        "failed to compress files or folders",
+		ErrorFileArchiveNameTooLong:    "archive name too long",
+		ErrorFileExtractFailed:         "failed to extract files",
+		ErrorFileNotArchive:            "cannot open the file as archive",
+		ErrorFileArchiveRead:           "failed to read archive data",
+		ErrorFileWrongPassword:         "wrong password",
+		ErrorFileArchiveList:           "failed to list the files in the archive",
+		ErrorFileArchiveItemNotFound:   "failed to find the item in the archive",
+		ErrorFileUploadNoLength:        "upload is missing the content length",
+		ErrorFileUploadTimeout:         "upload timed out",
+		ErrorFileUploadNoName:          "upload is missing the file name",
+		ErrorFileUploadCancelled:       "upload was cancelled",
+		ErrorFileUploadFATTooBig:       "cannot upload a file bigger than 4G to a FAT32 file system",
+		ErrorFileUploadNoOverwrite:     "cannot overwrite or skip the existing file without an overwrite mode",
+	})
+}
+
+// FileError is the error for an individual path reported by a FileStation
+// batch operation. It is described along with the ResponseError it belongs to.
+type FileError = ErrorDetail
+
+// FileErrors returns the per-path errors included in the error of a
+// FileStation response, such as one obtained using DoRaw. They are the
+// Details, but it fails if the errors are not an array.
+func (e ResponseError) FileErrors() ([]FileError, error) {
+	if len(e.Errors) == 0 {
+		return nil, nil
+	}
+	if e.Details == nil {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(e.Errors, &raw); err != nil {
+			return nil, err
+		}
+	}
+	return e.Details, nil
+}
+
+const (
+	fileStationPath = "/webapi/entry.cgi"
+
+	fileInfoAPI     = "SYNO.FileStation.Info"
+	fileInfoVersion = "2"
+
+	fileListAPI     = "SYNO.FileStation.List"
+	fileListVersion = "2"
+
+	fileVirtualFolderAPI     = "SYNO.FileStation.VirtualFolder"
+	fileVirtualFolderVersion = "2"
+
+	fileMountAPI     = "SYNO.FileStation.Mount"
+	fileMountVersion = "1"
+
+	fileCheckPermissionAPI     = "SYNO.FileStation.CheckPermission"
+	fileCheckPermissionVersion = "3"
+
+	fileCreateFolderAPI     = "SYNO.FileStation.CreateFolder"
+	fileCreateFolderVersion = "2"
+
+	fileDeleteAPI     = "SYNO.FileStation.Delete"
+	fileDeleteVersion = "2"
+)
+
+// fileStationRequest builds the Request for a FileStation API method with the
+// parameters from v.
+func fileStationRequest(api, version, method string, v interface{}) (*Request, error) {
+	p, err := MarshalParams(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		Path:    fileStationPath,
+		API:     api,
+		Version: version,
+		Method:  method,
+		Params:  p,
+		Session: SessionFileStation,
+	}, nil
+}
+
+// FileStationInfo gets information about File Station and the permissions of
+// the user, which also makes it a cheap check that FileStation is reachable.
+// The response is FileStationInfoResponse.
+type FileStationInfo struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationInfo) MarshalRequest() (*Request, error) {
+	r, err := fileStationRequest(fileInfoAPI, fileInfoVersion, "get", f)
+	if err != nil {
+		return nil, err
+	}
+	r.Cacheable = true
+	return r, nil
+}
+
+// FileStationInfoResponse is the response for FileStationInfo.
+type FileStationInfoResponse struct {
+	Hostname string `json:"hostname"`
+
+	// IsManager reports if the user is an administrator.
+	IsManager bool `json:"is_manager"`
+
+	// SupportSharing reports if the user can share files with links.
+	SupportSharing bool `json:"support_sharing"`
+
+	// SupportVirtualProtocol lists the virtual file systems the user can mount,
+	// such as "cifs" and "iso".
+	SupportVirtualProtocol string `json:"support_virtual_protocol"`
+}
+
+// Additional information that can be requested for FileStation entries. Not
+// every API supports all of them, such as size and type which only apply to
+// files.
+const (
+	FileAdditionalRealPath       = "real_path"
+	FileAdditionalSize           = "size"
+	FileAdditionalOwner          = "owner"
+	FileAdditionalTime           = "time"
+	FileAdditionalPerm           = "perm"
+	FileAdditionalType           = "type"
+	FileAdditionalMountPointType = "mount_point_type"
+	FileAdditionalVolumeStatus   = "volume_status"
+)
+
+// FileStationListShare lists the shared folders. The response is
+// FileShareListResponse.
+type FileStationListShare struct {
+	Offset        int           `syno:"offset,omitempty"`
+	Limit         int           `syno:"limit,omitempty"`
+	SortBy        FileSortBy    `syno:"sort_by,omitempty"`
+	SortDirection SortDirection `syno:"sort_direction,omitempty"`
+	OnlyWritable  bool          `syno:"onlywritable,omitempty"`
+	Additional    []string      `syno:"additional,omitempty"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationListShare) MarshalRequest() (*Request, error) {
+	r, err := fileStationRequest(fileListAPI, fileListVersion, "list_share", f)
+	if err != nil {
+		return nil, err
+	}
+	r.Cacheable = true
+	return r, nil
+}
+
+// FileShare is a shared folder as returned by FileStationListShare. The
+// Additional details are only included if requested.
+type FileShare struct {
+	Path       string               `json:"path"`
+	Name       string               `json:"name"`
+	IsDir      bool                 `json:"isdir"`
+	Additional *FileShareAdditional `json:"additional,omitempty"`
+}
+
+// FileShareListResponse is the response from a FileStationListShare request.
+type FileShareListResponse = ListResponse[FileShare]
+
+// FileShareAdditional holds the details requested with
+// FileStationListShare.Additional.
+type FileShareAdditional struct {
+	RealPath       string            `json:"real_path"`
+	Owner          *FileOwner        `json:"owner,omitempty"`
+	Time           *FileTime         `json:"time,omitempty"`
+	Perm           *FileSharePerm    `json:"perm,omitempty"`
+	MountPointType string            `json:"mount_point_type"`
+	VolumeStatus   *FileVolumeStatus `json:"volume_status,omitempty"`
+}
+
+// FileOwner is the "owner" additional information of a FileStation entry.
+type FileOwner struct {
+	User  string `json:"user"`
+	Group string `json:"group"`
+	UID   int    `json:"uid"`
+	GID   int    `json:"gid"`
+}
+
+// FileTime is the "time" additional information of a FileStation entry.
+type FileTime struct {
+	Accessed Time `json:"atime"`
+	Modified Time `json:"mtime"`
+	Changed  Time `json:"ctime"`
+	Created  Time `json:"crtime"`
+}
+
+// FileACL is the access the user has to a FileStation entry through its ACL.
+type FileACL struct {
+	Append bool `json:"append"`
+	Delete bool `json:"del"`
+	Exec   bool `json:"exec"`
+	Read   bool `json:"read"`
+	Write  bool `json:"write"`
+}
+
+// FileAdvancedRight are the advanced share permissions applying to the user.
+type FileAdvancedRight struct {
+	DisableDownload bool `json:"disable_download"`
+	DisableList     bool `json:"disable_list"`
+	DisableModify   bool `json:"disable_modify"`
+}
+
+// FileSharePerm is the "perm" additional information of a shared folder.
+// ShareRight is "RW", "RO" or "-" for no access, and Posix is the octal mode
+// such as 777.
+type FileSharePerm struct {
+	ShareRight    string             `json:"share_right"`
+	Posix         int                `json:"posix"`
+	AdvancedRight *FileAdvancedRight `json:"adv_right,omitempty"`
+	ACLEnabled    bool               `json:"acl_enable"`
+	IsACLMode     bool               `json:"is_acl_mode"`
+	ACL           *FileACL           `json:"acl,omitempty"`
+}
+
+// FileVolumeStatus is the "volume_status" additional information of a shared
+// folder.
+type FileVolumeStatus struct {
+	FreeSpace  Bytes `json:"freespace"`
+	TotalSpace Bytes `json:"totalspace"`
+	ReadOnly   bool  `json:"readonly"`
+}
+
+// FileStationList lists the files in a folder. The response is
+// FileListResponse.
+type FileStationList struct {
+	FolderPath    string        `syno:"folder_path"`
+	Offset        int           `syno:"offset,omitempty"`
+	Limit         int           `syno:"limit,omitempty"`
+	SortBy        FileSortBy    `syno:"sort_by,omitempty"`
+	SortDirection SortDirection `syno:"sort_direction,omitempty"`
+
+	// Pattern filters the files by name using comma separated glob patterns.
+	Pattern string `syno:"pattern,omitempty"`
+
+	FileType   FileType `syno:"filetype,omitempty"`
+	Additional []string `syno:"additional,omitempty"`
+}
+
+// Validate checks that the folder path is given.
+func (f FileStationList) Validate() error {
+	return requireField("FolderPath", f.FolderPath)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationList) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileListAPI, fileListVersion, "list", f)
+}
+
+// File is a file or folder as returned by FileStationList. The Additional
+// details are only included if requested.
+type File struct {
+	Path       string          `json:"path"`
+	Name       string          `json:"name"`
+	IsDir      bool            `json:"isdir"`
+	Additional *FileAdditional `json:"additional,omitempty"`
+}
+
+// FileListResponse is the response from a FileStationList request.
+type FileListResponse = ListResponse[File]
+
+// FileAdditional holds the details requested with FileStationList.Additional.
+// Type is the file extension, and is empty for folders.
+type FileAdditional struct {
+	RealPath       string     `json:"real_path"`
+	Size           Bytes      `json:"size"`
+	Owner          *FileOwner `json:"owner,omitempty"`
+	Time           *FileTime  `json:"time,omitempty"`
+	Perm           *FilePerm  `json:"perm,omitempty"`
+	MountPointType string     `json:"mount_point_type"`
+	Type           string     `json:"type"`
+}
+
+// FilePerm is the "perm" additional information of a file or folder. Posix is
+// the octal mode such as 755.
+type FilePerm struct {
+	Posix     int      `json:"posix"`
+	IsACLMode bool     `json:"is_acl_mode"`
+	ACL       *FileACL `json:"acl,omitempty"`
+}
+
+// FileStationListVirtualFolder lists the mount points of remote folders of a
+// type. The response is VirtualFolderListResponse.
+type FileStationListVirtualFolder struct {
+	Type          VirtualFolderType `syno:"type"`
+	Offset        int               `syno:"offset,omitempty"`
+	Limit         int               `syno:"limit,omitempty"`
+	SortBy        FileSortBy        `syno:"sort_by,omitempty"`
+	SortDirection SortDirection     `syno:"sort_direction,omitempty"`
+	Additional    []string          `syno:"additional,omitempty"`
+}
+
+// Validate checks that the type is known.
+func (f FileStationListVirtualFolder) Validate() error {
+	if !f.Type.Valid() {
+		return &ValidationError{Field: "Type", Reason: "must be cifs, nfs or iso"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationListVirtualFolder) MarshalRequest() (*Request, error) {
+	return fileStationRequest(
+		fileVirtualFolderAPI, fileVirtualFolderVersion, "list", f)
+}
+
+// VirtualFolder is the mount point of a remote folder as returned by
+// FileStationListVirtualFolder. The Additional details are only included if
+// requested.
+type VirtualFolder struct {
+	Path       string                   `json:"path"`
+	Name       string                   `json:"name"`
+	Additional *VirtualFolderAdditional `json:"additional,omitempty"`
+}
+
+// VirtualFolderListResponse is the response from a
+// FileStationListVirtualFolder request.
+type VirtualFolderListResponse = ListResponse[VirtualFolder]
+
+// VirtualFolderAdditional holds the details requested with
+// FileStationListVirtualFolder.Additional.
+type VirtualFolderAdditional struct {
+	RealPath       string            `json:"real_path"`
+	Owner          *FileOwner        `json:"owner,omitempty"`
+	Time           *FileTime         `json:"time,omitempty"`
+	Perm           *FilePerm         `json:"perm,omitempty"`
+	MountPointType string            `json:"mount_point_type"`
+	VolumeStatus   *FileVolumeStatus `json:"volume_status,omitempty"`
+}
+
+// FileStationMountRemote mounts a remote CIFS or NFS folder at an empty
+// folder on the server, after which it is listed by
+// FileStationListVirtualFolder. The Mount API is not part of the published
+// FileStation API, and its parameters are those sent by the File Station web
+// interface. It does not have a response.
+type FileStationMountRemote struct {
+	Type VirtualFolderType `syno:"mount_type"`
+
+	// Server is the remote folder, such as "//host/share" for CIFS or
+	// "host:/volume1/share" for NFS.
+	Server string `syno:"server"`
+
+	// MountPoint is the empty folder to mount the remote folder at.
+	MountPoint string `syno:"mount_point"`
+
+	// User and Password are the CIFS credentials, and are not used for NFS.
+	User     string `syno:"user,omitempty"`
+	Password string `syno:"pass,omitempty"`
+
+	// AutoMount mounts the folder again when the server starts.
+	AutoMount bool `syno:"auto_mount,omitempty"`
+}
+
+// Validate checks that the type is CIFS or NFS and that the server and mount
+// point are given.
+func (f FileStationMountRemote) Validate() error {
+	if f.Type != VirtualFolderCIFS && f.Type != VirtualFolderNFS {
+		return &ValidationError{Field: "Type", Reason: "must be cifs or nfs"}
+	}
+	if err := requireField("Server", f.Server); err != nil {
+		return err
+	}
+	return requireField("MountPoint", f.MountPoint)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationMountRemote) MarshalRequest() (*Request, error) {
+	r, err := fileStationRequest(fileMountAPI, fileMountVersion, "mount_remote", f)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+// FileStationUnmount unmounts a remote folder mounted with
+// FileStationMountRemote. It does not have a response.
+type FileStationUnmount struct {
+	Type       VirtualFolderType `syno:"mount_type"`
+	MountPoint string            `syno:"mount_point"`
+}
+
+// Validate checks that the type is known and the mount point is given.
+func (f FileStationUnmount) Validate() error {
+	if !f.Type.Valid() {
+		return &ValidationError{Field: "Type", Reason: "must be cifs, nfs or iso"}
+	}
+	return requireField("MountPoint", f.MountPoint)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationUnmount) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileMountAPI, fileMountVersion, "unmount", f)
+}
+
+// FileStationCheckPermission checks that the user can write a file with the
+// name to the folder, such as before uploading it. It succeeds without a
+// response if the file can be written and fails with an error such as
+// ErrorFileNotPermitted or ErrorFileExists otherwise.
+type FileStationCheckPermission struct {
+	Path     string `syno:"path"`
+	Filename string `syno:"filename"`
+
+	// Overwrite checks that an existing file can be overwritten, otherwise
+	// an existing file is reported as an error.
+	Overwrite bool `syno:"overwrite,omitempty"`
+
+	// CreateOnly checks that the file does not exist yet if true, or that an
+	// existing file can be written over if false. If nil, the server default
+	// of true is used.
+	CreateOnly *bool `syno:"create_only"`
+}
+
+// Validate checks that the path and file name are given.
+func (f FileStationCheckPermission) Validate() error {
+	if err := requireField("Path", f.Path); err != nil {
+		return err
+	}
+	return requireField("Filename", f.Filename)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationCheckPermission) MarshalRequest() (*Request, error) {
+	return fileStationRequest(
+		fileCheckPermissionAPI, fileCheckPermissionVersion, "write", f)
+}
+
+// FileStationCreateFolder creates folders, each with the name at the same
+// index in Names inside the folder at that index in FolderPaths. The response
+// is FolderCreateResponse.
+type FileStationCreateFolder struct {
+	FolderPaths []string `syno:"folder_path,json"`
+	Names       []string `syno:"name,json"`
+
+	// ForceParent creates the parent folders if they do not exist, and does
+	// not fail if the folder already exists.
+	ForceParent bool `syno:"force_parent,omitempty"`
+
+	Additional []string `syno:"additional,omitempty"`
+}
+
+// Validate checks that there is a name for every folder path.
+func (f FileStationCreateFolder) Validate() error {
+	if len(f.FolderPaths) == 0 {
+		return &ValidationError{Field: "FolderPaths", Reason: "required"}
+	}
+	if len(f.Names) != len(f.FolderPaths) {
+		return &ValidationError{
+			Field:  "Names",
+			Reason: "must have one name for every folder path",
+		}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationCreateFolder) MarshalRequest() (*Request, error) {
+	return fileStationRequest(
+		fileCreateFolderAPI, fileCreateFolderVersion, "create", f)
+}
+
+// FolderCreateResponse is the response from a FileStationCreateFolder
+// request, with the created folders in the order they were requested.
+type FolderCreateResponse struct {
+	Folders []File `json:"folders"`
+}
+
+// FileStationDelete deletes files and folders, waiting for the deletion to
+// finish before responding. It does not have a response.
+type FileStationDelete struct {
+	Paths []string `syno:"path,json"`
+
+	// Recursive deletes folders along with their contents, otherwise only
+	// empty folders can be deleted.
+	Recursive bool `syno:"recursive"`
+}
+
+// Validate checks that at least one path is given.
+func (f FileStationDelete) Validate() error {
+	if len(f.Paths) == 0 {
+		return &ValidationError{Field: "Paths", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationDelete) MarshalRequest() (*Request, error) {
+	r, err := fileStationRequest(fileDeleteAPI, fileDeleteVersion, "delete", f)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
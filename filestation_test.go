@@ -0,0 +1,397 @@
+package syno
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestFileStationErrorStrings(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		ErrorString("SYNO.FileStation.Delete", ErrorFileDeleteFailed),
+		"syno: failed to delete files or folders (900)",
+	)
+	err := &ResponseError{
+		API:     "SYNO.FileStation.Delete",
+		Code:    ErrorFileDeleteFailed,
+		Details: []FileError{{Code: ErrorFileNotExist, Path: "/home/a"}},
+	}
+	ensure.DeepEqual(t, err.Error(),
+		"syno: failed to delete files or folders (900): /home/a: no such file or directory (408)")
+}
+
+func TestResponseErrorFileErrors(t *testing.T) {
+	var res Response
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"success": false,
+		"error": {
+			"code": 900,
+			"errors": [
+				{"code": 408, "path": "/home/a"},
+				{"code": 407, "path": "/home/b"}
+			]
+		}
+	}`), &res))
+	errs, err := res.Error.FileErrors()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, errs, []FileError{
+		{
+			Code: ErrorFileNotExist,
+			Path: "/home/a",
+			Raw:  json.RawMessage(`{"code": 408, "path": "/home/a"}`),
+		},
+		{
+			Code: ErrorFileNotPermitted,
+			Path: "/home/b",
+			Raw:  json.RawMessage(`{"code": 407, "path": "/home/b"}`),
+		},
+	})
+
+	errs, err = ResponseError{Code: ErrorFileDeleteFailed}.FileErrors()
+	ensure.Nil(t, err)
+	ensure.True(t, errs == nil)
+
+	_, err = ResponseError{Errors: json.RawMessage(`{}`)}.FileErrors()
+	ensure.NotNil(t, err)
+}
+
+func TestFileStationInfoMarshal(t *testing.T) {
+	r, err := FileStationInfo{}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:      fileStationPath,
+		API:       fileInfoAPI,
+		Version:   fileInfoVersion,
+		Method:    "get",
+		Params:    url.Values{},
+		Session:   SessionFileStation,
+		Cacheable: true,
+	})
+}
+
+func TestFileStationInfoResponseUnmarshal(t *testing.T) {
+	var res FileStationInfoResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"hostname": "nas",
+		"is_manager": true,
+		"support_sharing": true,
+		"support_virtual_protocol": "cifs,iso"
+	}`), &res))
+	ensure.DeepEqual(t, res, FileStationInfoResponse{
+		Hostname:               "nas",
+		IsManager:              true,
+		SupportSharing:         true,
+		SupportVirtualProtocol: "cifs,iso",
+	})
+}
+
+func TestFileStationListShareMarshal(t *testing.T) {
+	r, err := FileStationListShare{
+		Limit:         10,
+		SortBy:        FileSortName,
+		SortDirection: SortDescending,
+		OnlyWritable:  true,
+		Additional:    []string{FileAdditionalRealPath, FileAdditionalVolumeStatus},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileListAPI,
+		Version: fileListVersion,
+		Method:  "list_share",
+		Params: url.Values{
+			"limit":          []string{"10"},
+			"sort_by":        []string{"name"},
+			"sort_direction": []string{"desc"},
+			"onlywritable":   []string{"true"},
+			"additional":     []string{"real_path,volume_status"},
+		},
+		Session:   SessionFileStation,
+		Cacheable: true,
+	})
+}
+
+func TestFileShareListResponseUnmarshal(t *testing.T) {
+	var res FileShareListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"offset": 0,
+		"total": 1,
+		"shares": [{
+			"isdir": true,
+			"name": "video",
+			"path": "/video",
+			"additional": {
+				"real_path": "/volume1/video",
+				"owner": {"user": "admin", "group": "users", "uid": 1024, "gid": 100},
+				"perm": {
+					"share_right": "RW",
+					"posix": 777,
+					"adv_right": {"disable_download": true},
+					"acl": {"read": true, "write": true}
+				},
+				"volume_status": {"freespace": 1024, "totalspace": "4096", "readonly": false}
+			}
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, FileShareListResponse{
+		Total: 1,
+		Items: []FileShare{{
+			Path:  "/video",
+			Name:  "video",
+			IsDir: true,
+			Additional: &FileShareAdditional{
+				RealPath: "/volume1/video",
+				Owner:    &FileOwner{User: "admin", Group: "users", UID: 1024, GID: 100},
+				Perm: &FileSharePerm{
+					ShareRight:    "RW",
+					Posix:         777,
+					AdvancedRight: &FileAdvancedRight{DisableDownload: true},
+					ACL:           &FileACL{Read: true, Write: true},
+				},
+				VolumeStatus: &FileVolumeStatus{FreeSpace: 1024, TotalSpace: 4096},
+			},
+		}},
+	})
+}
+
+func TestFileStationListMarshal(t *testing.T) {
+	r, err := FileStationList{
+		FolderPath: "/video",
+		Pattern:    "*.mkv,*.mp4",
+		FileType:   FileTypeFile,
+		Additional: []string{FileAdditionalSize, FileAdditionalTime},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileListAPI,
+		Version: fileListVersion,
+		Method:  "list",
+		Params: url.Values{
+			"folder_path": []string{"/video"},
+			"pattern":     []string{"*.mkv,*.mp4"},
+			"filetype":    []string{"file"},
+			"additional":  []string{"size,time"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFileListResponseUnmarshal(t *testing.T) {
+	var res FileListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"offset": 0,
+		"total": 1,
+		"files": [{
+			"isdir": false,
+			"name": "a.mkv",
+			"path": "/video/a.mkv",
+			"additional": {
+				"size": 2048,
+				"type": "MKV",
+				"time": {"atime": 1500000000, "mtime": 1500000001, "ctime": 0, "crtime": 0},
+				"perm": {"posix": 755, "is_acl_mode": false}
+			}
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, FileListResponse{
+		Total: 1,
+		Items: []File{{
+			Path: "/video/a.mkv",
+			Name: "a.mkv",
+			Additional: &FileAdditional{
+				Size: 2048,
+				Type: "MKV",
+				Time: &FileTime{
+					Accessed: Time{time.Unix(1500000000, 0)},
+					Modified: Time{time.Unix(1500000001, 0)},
+				},
+				Perm: &FilePerm{Posix: 755},
+			},
+		}},
+	})
+}
+
+func TestFileStationListVirtualFolderMarshal(t *testing.T) {
+	r, err := FileStationListVirtualFolder{
+		Type:       VirtualFolderCIFS,
+		Additional: []string{FileAdditionalRealPath},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileVirtualFolderAPI,
+		Version: fileVirtualFolderVersion,
+		Method:  "list",
+		Params: url.Values{
+			"type":       []string{"cifs"},
+			"additional": []string{"real_path"},
+		},
+		Session: SessionFileStation,
+	})
+	ensure.DeepEqual(
+		t,
+		validate(FileStationListVirtualFolder{}),
+		&ValidationError{Field: "Type", Reason: "must be cifs, nfs or iso"},
+	)
+}
+
+func TestVirtualFolderListResponseUnmarshal(t *testing.T) {
+	var res VirtualFolderListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"offset": 0,
+		"total": 1,
+		"folders": [{
+			"name": "remote",
+			"path": "/home/remote",
+			"additional": {
+				"real_path": "/volume1/homes/admin/remote",
+				"mount_point_type": "remote",
+				"volume_status": {"freespace": 10, "totalspace": 20, "readonly": true}
+			}
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, VirtualFolderListResponse{
+		Total: 1,
+		Items: []VirtualFolder{{
+			Path: "/home/remote",
+			Name: "remote",
+			Additional: &VirtualFolderAdditional{
+				RealPath:       "/volume1/homes/admin/remote",
+				MountPointType: "remote",
+				VolumeStatus: &FileVolumeStatus{
+					FreeSpace:  10,
+					TotalSpace: 20,
+					ReadOnly:   true,
+				},
+			},
+		}},
+	})
+}
+
+func TestFileStationCheckPermissionMarshal(t *testing.T) {
+	r, err := FileStationCheckPermission{
+		Path:      "/video",
+		Filename:  "a.mkv",
+		Overwrite: true,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileCheckPermissionAPI,
+		Version: fileCheckPermissionVersion,
+		Method:  "write",
+		Params: url.Values{
+			"path":      []string{"/video"},
+			"filename":  []string{"a.mkv"},
+			"overwrite": []string{"true"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFileStationCheckPermissionCreateOnlyFalse(t *testing.T) {
+	createOnly := false
+	r, err := FileStationCheckPermission{
+		Path:       "/video",
+		Filename:   "a.mkv",
+		CreateOnly: &createOnly,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Params, url.Values{
+		"path":        []string{"/video"},
+		"filename":    []string{"a.mkv"},
+		"create_only": []string{"false"},
+	})
+}
+
+func TestFileStationCreateFolderMarshal(t *testing.T) {
+	r, err := FileStationCreateFolder{
+		FolderPaths: []string{"/video", "/music"},
+		Names:       []string{"a", "b/c"},
+		ForceParent: true,
+		Additional:  []string{FileAdditionalRealPath},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileCreateFolderAPI,
+		Version: fileCreateFolderVersion,
+		Method:  "create",
+		Params: url.Values{
+			"folder_path":  []string{`["/video","/music"]`},
+			"name":         []string{`["a","b/c"]`},
+			"force_parent": []string{"true"},
+			"additional":   []string{"real_path"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFolderCreateResponseUnmarshal(t *testing.T) {
+	var res FolderCreateResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"folders": [
+			{"isdir": true, "name": "a", "path": "/video/a"},
+			{"isdir": true, "name": "c", "path": "/music/b/c"}
+		]
+	}`), &res))
+	ensure.DeepEqual(t, res, FolderCreateResponse{
+		Folders: []File{
+			{Path: "/video/a", Name: "a", IsDir: true},
+			{Path: "/music/b/c", Name: "c", IsDir: true},
+		},
+	})
+}
+
+func TestFileStationMountRemoteMarshal(t *testing.T) {
+	r, err := FileStationMountRemote{
+		Type:       VirtualFolderCIFS,
+		Server:     "//nas2/video",
+		MountPoint: "/video/nas2",
+		User:       "a",
+		Password:   "b",
+		AutoMount:  true,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       fileStationPath,
+		API:        fileMountAPI,
+		Version:    fileMountVersion,
+		Method:     "mount_remote",
+		HTTPMethod: http.MethodPost,
+		Params: url.Values{
+			"mount_type":  []string{"cifs"},
+			"server":      []string{"//nas2/video"},
+			"mount_point": []string{"/video/nas2"},
+			"user":        []string{"a"},
+			"pass":        []string{"b"},
+			"auto_mount":  []string{"true"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFileStationUnmountMarshal(t *testing.T) {
+	r, err := FileStationUnmount{
+		Type:       VirtualFolderNFS,
+		MountPoint: "/video/nas2",
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileMountAPI,
+		Version: fileMountVersion,
+		Method:  "unmount",
+		Params: url.Values{
+			"mount_type":  []string{"nfs"},
+			"mount_point": []string{"/video/nas2"},
+		},
+		Session: SessionFileStation,
+	})
+}
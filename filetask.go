@@ -0,0 +1,384 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/daaku/syno/params"
+)
+
+const (
+	fileDirSizeAPI     = "SYNO.FileStation.DirSize"
+	fileDirSizeVersion = "2"
+
+	fileMD5API     = "SYNO.FileStation.MD5"
+	fileMD5Version = "2"
+
+	fileCopyMoveAPI     = "SYNO.FileStation.CopyMove"
+	fileCopyMoveVersion = "3"
+
+	fileBackgroundTaskAPI     = "SYNO.FileStation.BackgroundTask"
+	fileBackgroundTaskVersion = "3"
+)
+
+// poll calls f every interval until it reports being done, fails or the
+// context is done. The first call is made immediately.
+func poll(ctx context.Context, interval time.Duration, f func() (bool, error)) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		done, err := f()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// stopFileTask makes the request to stop a background FileStation task on a
+// best effort basis, even if the context of the caller is done.
+func (c *Client) stopFileTask(r MarshalRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	c.Call(ctx, r, nil)
+}
+
+// FileStationDirSizeStart starts calculating the total size of the files and
+// folders. The calculation runs in the background and its progress is
+// fetched with FileStationDirSizeStatus. The response is FileTaskResponse.
+type FileStationDirSizeStart struct {
+	Paths []string `syno:"path,json"`
+}
+
+// Validate checks that at least one path is given.
+func (f FileStationDirSizeStart) Validate() error {
+	if len(f.Paths) == 0 {
+		return &ValidationError{Field: "Paths", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationDirSizeStart) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileDirSizeAPI, fileDirSizeVersion, "start", f)
+}
+
+// FileTaskResponse is the response for requests starting a background
+// FileStation task.
+type FileTaskResponse struct {
+	TaskID string `json:"taskid"`
+}
+
+// FileStationDirSizeStatus gets the progress of a size calculation. The
+// response is FileDirSizeStatus.
+type FileStationDirSizeStatus struct {
+	TaskID string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationDirSizeStatus) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileDirSizeAPI, fileDirSizeVersion, "status", f)
+}
+
+// FileDirSizeStatus is the response for FileStationDirSizeStatus. The totals
+// are those counted so far until Finished is true.
+type FileDirSizeStatus struct {
+	Finished  bool  `json:"finished"`
+	NumDir    int   `json:"num_dir"`
+	NumFile   int   `json:"num_file"`
+	TotalSize Bytes `json:"total_size"`
+}
+
+// FileStationDirSizeStop stops a size calculation. It does not have a
+// response.
+type FileStationDirSizeStop struct {
+	TaskID string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationDirSizeStop) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileDirSizeAPI, fileDirSizeVersion, "stop", f)
+}
+
+// DirSize calculates the total size of the files and folders, polling every
+// interval until the calculation finishes. The calculation is stopped before
+// returning, even if the context is done.
+func (c *Client) DirSize(
+	ctx context.Context,
+	paths []string,
+	interval time.Duration,
+) (FileDirSizeStatus, error) {
+	var start FileTaskResponse
+	if err := c.Call(ctx, FileStationDirSizeStart{Paths: paths}, &start); err != nil {
+		return FileDirSizeStatus{}, err
+	}
+	defer c.stopFileTask(FileStationDirSizeStop{TaskID: start.TaskID})
+
+	var status FileDirSizeStatus
+	err := poll(ctx, interval, func() (bool, error) {
+		status = FileDirSizeStatus{}
+		err := c.Call(ctx, FileStationDirSizeStatus{TaskID: start.TaskID}, &status)
+		return status.Finished, err
+	})
+	if err != nil {
+		return FileDirSizeStatus{}, err
+	}
+	return status, nil
+}
+
+// FileStationMD5Start starts calculating the MD5 of a file. The calculation
+// runs in the background and its result is fetched with
+// FileStationMD5Status. The response is FileTaskResponse.
+type FileStationMD5Start struct {
+	FilePath string `syno:"file_path"`
+}
+
+// Validate checks that the file path is given.
+func (f FileStationMD5Start) Validate() error {
+	return requireField("FilePath", f.FilePath)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationMD5Start) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileMD5API, fileMD5Version, "start", f)
+}
+
+// FileStationMD5Status gets the status of an MD5 calculation. The response is
+// FileMD5Status.
+type FileStationMD5Status struct {
+	TaskID string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationMD5Status) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileMD5API, fileMD5Version, "status", f)
+}
+
+// FileMD5Status is the response for FileStationMD5Status. MD5 is the hex
+// encoded digest once Finished is true.
+type FileMD5Status struct {
+	Finished bool   `json:"finished"`
+	MD5      string `json:"md5"`
+}
+
+// FileStationMD5Stop stops an MD5 calculation. It does not have a response.
+type FileStationMD5Stop struct {
+	TaskID string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationMD5Stop) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileMD5API, fileMD5Version, "stop", f)
+}
+
+// FileMD5 calculates the hex encoded MD5 of the file on the server, polling
+// every interval until the calculation finishes. The calculation is stopped
+// before returning, even if the context is done.
+func (c *Client) FileMD5(ctx context.Context, path string, interval time.Duration) (string, error) {
+	var start FileTaskResponse
+	if err := c.Call(ctx, FileStationMD5Start{FilePath: path}, &start); err != nil {
+		return "", err
+	}
+	defer c.stopFileTask(FileStationMD5Stop{TaskID: start.TaskID})
+
+	var status FileMD5Status
+	err := poll(ctx, interval, func() (bool, error) {
+		status = FileMD5Status{}
+		err := c.Call(ctx, FileStationMD5Status{TaskID: start.TaskID}, &status)
+		return status.Finished, err
+	})
+	if err != nil {
+		return "", err
+	}
+	return status.MD5, nil
+}
+
+// FileStationCopyMoveStart starts copying, or moving if RemoveSource is set,
+// the files and folders into a folder. The data is copied on the server in
+// the background and its progress is fetched with FileStationCopyMoveStatus.
+// The response is FileTaskResponse.
+type FileStationCopyMoveStart struct {
+	Paths      []string
+	DestFolder string
+
+	// RemoveSource moves the files instead of copying them.
+	RemoveSource bool
+
+	Overwrite FileOverwriteMode
+
+	// AccurateProgress calculates the total size before starting, which
+	// makes the progress accurate at the cost of a slower start.
+	AccurateProgress bool
+}
+
+// Validate checks that the paths and destination folder are given.
+func (f FileStationCopyMoveStart) Validate() error {
+	if len(f.Paths) == 0 {
+		return &ValidationError{Field: "Paths", Reason: "required"}
+	}
+	if err := requireField("DestFolder", f.DestFolder); err != nil {
+		return err
+	}
+	if !f.Overwrite.Valid() {
+		return &ValidationError{Field: "Overwrite", Reason: "unknown mode"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationCopyMoveStart) MarshalRequest() (*Request, error) {
+	p := url.Values{
+		"dest_folder_path":  []string{f.DestFolder},
+		"remove_src":        []string{strconv.FormatBool(f.RemoveSource)},
+		"accurate_progress": []string{strconv.FormatBool(f.AccurateProgress)},
+	}
+	params.SetJSONArray(p, "path", f.Paths)
+	switch f.Overwrite {
+	case FileOverwrite:
+		p.Set("overwrite", "true")
+	case FileOverwriteSkip:
+		p.Set("overwrite", "false")
+	}
+	return &Request{
+		Path:    fileStationPath,
+		API:     fileCopyMoveAPI,
+		Version: fileCopyMoveVersion,
+		Method:  "start",
+		Params:  p,
+		Session: SessionFileStation,
+	}, nil
+}
+
+// FileStationCopyMoveStatus gets the progress of a copy or move. The response
+// is FileCopyMoveStatus.
+type FileStationCopyMoveStatus struct {
+	TaskID string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationCopyMoveStatus) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileCopyMoveAPI, fileCopyMoveVersion, "status", f)
+}
+
+// FileCopyMoveStatus is the response for FileStationCopyMoveStatus. Total is
+// -1 while the size is still being calculated, and Path is the file being
+// copied.
+type FileCopyMoveStatus struct {
+	Finished      bool    `json:"finished"`
+	Progress      float64 `json:"progress"`
+	ProcessedSize Bytes   `json:"processed_size"`
+	Total         Bytes   `json:"total"`
+	Path          string  `json:"path"`
+	DestFolder    string  `json:"dest_folder_path"`
+}
+
+// FileStationCopyMoveStop stops a copy or move. Files already copied are not
+// removed. It does not have a response.
+type FileStationCopyMoveStop struct {
+	TaskID string `syno:"taskid"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationCopyMoveStop) MarshalRequest() (*Request, error) {
+	return fileStationRequest(fileCopyMoveAPI, fileCopyMoveVersion, "stop", f)
+}
+
+// CopyMove copies or moves the files on the server, polling every interval
+// until it finishes. The progress, if given, is called with the bytes
+// processed and the total after every poll. The task is stopped if CopyMove
+// returns before it finishes, such as when the context is done.
+func (c *Client) CopyMove(
+	ctx context.Context,
+	s FileStationCopyMoveStart,
+	interval time.Duration,
+	progress ProgressFunc,
+) error {
+	var start FileTaskResponse
+	if err := c.Call(ctx, s, &start); err != nil {
+		return err
+	}
+	var finished bool
+	defer func() {
+		if !finished {
+			c.stopFileTask(FileStationCopyMoveStop{TaskID: start.TaskID})
+		}
+	}()
+
+	return poll(ctx, interval, func() (bool, error) {
+		var status FileCopyMoveStatus
+		err := c.Call(ctx, FileStationCopyMoveStatus{TaskID: start.TaskID}, &status)
+		if err != nil {
+			return false, err
+		}
+		if progress != nil {
+			progress(int64(status.ProcessedSize), int64(status.Total))
+		}
+		finished = status.Finished
+		return finished, nil
+	})
+}
+
+// FileStationBackgroundTaskList lists the background copy, move, delete,
+// compress and extract tasks of the user. The response is
+// FileBackgroundTaskListResponse.
+type FileStationBackgroundTaskList struct {
+	Offset int `syno:"offset,omitempty"`
+	Limit  int `syno:"limit,omitempty"`
+
+	// SortBy is FileSortCreated or "finished".
+	SortBy        FileSortBy    `syno:"sort_by,omitempty"`
+	SortDirection SortDirection `syno:"sort_direction,omitempty"`
+
+	// APIFilter lists only the tasks of these APIs, such as
+	// "SYNO.FileStation.CopyMove".
+	APIFilter []string `syno:"api_filter,omitempty"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationBackgroundTaskList) MarshalRequest() (*Request, error) {
+	return fileStationRequest(
+		fileBackgroundTaskAPI, fileBackgroundTaskVersion, "list", f)
+}
+
+// FileBackgroundTask is a background task as returned by
+// FileStationBackgroundTaskList. Params are those the task was started with,
+// and depend on the API.
+type FileBackgroundTask struct {
+	API            string          `json:"api"`
+	Version        int             `json:"version"`
+	Method         string          `json:"method"`
+	TaskID         string          `json:"taskid"`
+	Finished       bool            `json:"finished"`
+	Progress       float64         `json:"progress"`
+	Path           string          `json:"path"`
+	ProcessingPath string          `json:"processing_path"`
+	ProcessedNum   int             `json:"processed_num"`
+	ProcessedSize  Bytes           `json:"processed_size"`
+	Total          Bytes           `json:"total"`
+	Created        Time            `json:"crtime"`
+	Params         json.RawMessage `json:"params,omitempty"`
+}
+
+// FileBackgroundTaskListResponse is the response from a
+// FileStationBackgroundTaskList request.
+type FileBackgroundTaskListResponse = ListResponse[FileBackgroundTask]
+
+// FileStationBackgroundTaskClear removes finished background tasks from the
+// list. It does not have a response.
+type FileStationBackgroundTaskClear struct {
+	// TaskIDs are the finished tasks to remove, or all of them if empty.
+	TaskIDs []string `syno:"taskid,omitempty"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationBackgroundTaskClear) MarshalRequest() (*Request, error) {
+	return fileStationRequest(
+		fileBackgroundTaskAPI, fileBackgroundTaskVersion, "clear_finished", f)
+}
@@ -0,0 +1,303 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func fileTaskClient(t *testing.T, handle func(url.Values) string) *Client {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.Nil(t, r.ParseForm())
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":true,"data":` + handle(r.Form) + `}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+func TestPoll(t *testing.T) {
+	var calls int
+	err := poll(context.Background(), time.Microsecond, func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, calls, 3)
+
+	givenErr := errors.New("")
+	err = poll(context.Background(), time.Microsecond, func() (bool, error) {
+		return false, givenErr
+	})
+	ensure.DeepEqual(t, err, givenErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = poll(ctx, time.Hour, func() (bool, error) { return false, nil })
+	ensure.DeepEqual(t, err, context.Canceled)
+}
+
+func TestFileStationDirSizeMarshal(t *testing.T) {
+	r, err := FileStationDirSizeStart{Paths: []string{"/a", "/b"}}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileDirSizeAPI,
+		Version: fileDirSizeVersion,
+		Method:  "start",
+		Params:  url.Values{"path": []string{`["/a","/b"]`}},
+		Session: SessionFileStation,
+	})
+	ensure.DeepEqual(
+		t,
+		validate(FileStationDirSizeStart{}),
+		&ValidationError{Field: "Paths", Reason: "required"},
+	)
+}
+
+func TestClientDirSize(t *testing.T) {
+	var methods []string
+	var statuses int
+	c := fileTaskClient(t, func(v url.Values) string {
+		ensure.DeepEqual(t, v.Get("api"), fileDirSizeAPI)
+		methods = append(methods, v.Get("method"))
+		switch v.Get("method") {
+		case "start":
+			return `{"taskid":"t"}`
+		case "status":
+			ensure.DeepEqual(t, v.Get("taskid"), "t")
+			statuses++
+			if statuses == 1 {
+				return `{"finished":false,"num_file":1}`
+			}
+			return `{"finished":true,"num_dir":2,"num_file":3,"total_size":4096}`
+		}
+		ensure.DeepEqual(t, v.Get("taskid"), "t")
+		return "null"
+	})
+	status, err := c.DirSize(context.Background(), []string{"/a"}, time.Microsecond)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, status, FileDirSizeStatus{
+		Finished:  true,
+		NumDir:    2,
+		NumFile:   3,
+		TotalSize: 4096,
+	})
+	ensure.DeepEqual(t, methods, []string{"start", "status", "status", "stop"})
+}
+
+func TestFileStationMD5Marshal(t *testing.T) {
+	r, err := FileStationMD5Start{FilePath: "/a.mkv"}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileMD5API,
+		Version: fileMD5Version,
+		Method:  "start",
+		Params:  url.Values{"file_path": []string{"/a.mkv"}},
+		Session: SessionFileStation,
+	})
+	ensure.DeepEqual(
+		t,
+		validate(FileStationMD5Start{}),
+		&ValidationError{Field: "FilePath", Reason: "required"},
+	)
+}
+
+func TestClientFileMD5(t *testing.T) {
+	var methods []string
+	c := fileTaskClient(t, func(v url.Values) string {
+		ensure.DeepEqual(t, v.Get("api"), fileMD5API)
+		methods = append(methods, v.Get("method"))
+		switch v.Get("method") {
+		case "start":
+			ensure.DeepEqual(t, v.Get("file_path"), "/a.mkv")
+			return `{"taskid":"t"}`
+		case "status":
+			if len(methods) == 2 {
+				return `{"finished":false}`
+			}
+			return `{"finished":true,"md5":"d41d8cd98f00b204e9800998ecf8427e"}`
+		}
+		return "null"
+	})
+	sum, err := c.FileMD5(context.Background(), "/a.mkv", time.Microsecond)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, sum, "d41d8cd98f00b204e9800998ecf8427e")
+	ensure.DeepEqual(t, methods, []string{"start", "status", "status", "stop"})
+}
+
+func TestClientFileMD5StartError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":false,"error":{"code":408}}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	_, err = c.FileMD5(context.Background(), "/a.mkv", time.Microsecond)
+	ensure.True(t, errors.Is(err, ErrorFileNotExist))
+}
+
+func TestFileStationCopyMoveMarshal(t *testing.T) {
+	r, err := FileStationCopyMoveStart{
+		Paths:        []string{"/a", "/b"},
+		DestFolder:   "/c",
+		RemoveSource: true,
+		Overwrite:    FileOverwrite,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileCopyMoveAPI,
+		Version: fileCopyMoveVersion,
+		Method:  "start",
+		Params: url.Values{
+			"path":              []string{`["/a","/b"]`},
+			"dest_folder_path":  []string{"/c"},
+			"remove_src":        []string{"true"},
+			"accurate_progress": []string{"false"},
+			"overwrite":         []string{"true"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestClientCopyMove(t *testing.T) {
+	var methods []string
+	var statuses int
+	c := fileTaskClient(t, func(v url.Values) string {
+		ensure.DeepEqual(t, v.Get("api"), fileCopyMoveAPI)
+		methods = append(methods, v.Get("method"))
+		if v.Get("method") == "start" {
+			return `{"taskid":"t"}`
+		}
+		ensure.DeepEqual(t, v.Get("taskid"), "t")
+		statuses++
+		if statuses == 1 {
+			return `{"finished":false,"processed_size":1024,"total":4096}`
+		}
+		return `{"finished":true,"processed_size":4096,"total":4096}`
+	})
+	var done []int64
+	err := c.CopyMove(
+		context.Background(),
+		FileStationCopyMoveStart{Paths: []string{"/a"}, DestFolder: "/b"},
+		time.Microsecond,
+		func(bytesDone, bytesTotal int64) {
+			ensure.DeepEqual(t, bytesTotal, int64(4096))
+			done = append(done, bytesDone)
+		},
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, done, []int64{1024, 4096})
+	ensure.DeepEqual(t, methods, []string{"start", "status", "status"})
+}
+
+func TestClientCopyMoveStopsOnCancel(t *testing.T) {
+	var methods []string
+	ctx, cancel := context.WithCancel(context.Background())
+	c := fileTaskClient(t, func(v url.Values) string {
+		methods = append(methods, v.Get("method"))
+		switch v.Get("method") {
+		case "start":
+			return `{"taskid":"t"}`
+		case "status":
+			cancel()
+			return `{"finished":false,"total":-1}`
+		}
+		return "null"
+	})
+	err := c.CopyMove(
+		ctx,
+		FileStationCopyMoveStart{Paths: []string{"/a"}, DestFolder: "/b"},
+		time.Hour,
+		nil,
+	)
+	ensure.DeepEqual(t, err, context.Canceled)
+	ensure.DeepEqual(t, methods, []string{"start", "status", "stop"})
+}
+
+func TestFileStationBackgroundTaskListMarshal(t *testing.T) {
+	r, err := FileStationBackgroundTaskList{
+		Limit:     10,
+		SortBy:    FileSortCreated,
+		APIFilter: []string{fileCopyMoveAPI, fileExtractAPI},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileBackgroundTaskAPI,
+		Version: fileBackgroundTaskVersion,
+		Method:  "list",
+		Params: url.Values{
+			"limit":      []string{"10"},
+			"sort_by":    []string{"crtime"},
+			"api_filter": []string{"SYNO.FileStation.CopyMove,SYNO.FileStation.Extract"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFileBackgroundTaskListResponseUnmarshal(t *testing.T) {
+	var res FileBackgroundTaskListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"total": 1,
+		"offset": 0,
+		"tasks": [{
+			"api": "SYNO.FileStation.CopyMove",
+			"version": 3,
+			"method": "start",
+			"taskid": "t",
+			"finished": true,
+			"progress": 1,
+			"path": "/a",
+			"processed_size": 4096,
+			"total": 4096,
+			"crtime": 1500000000,
+			"params": {"remove_src": false}
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, FileBackgroundTaskListResponse{
+		Total: 1,
+		Items: []FileBackgroundTask{{
+			API:           fileCopyMoveAPI,
+			Version:       3,
+			Method:        "start",
+			TaskID:        "t",
+			Finished:      true,
+			Progress:      1,
+			Path:          "/a",
+			ProcessedSize: 4096,
+			Total:         4096,
+			Created:       Time{time.Unix(1500000000, 0)},
+			Params:        json.RawMessage(`{"remove_src": false}`),
+		}},
+	})
+}
+
+func TestFileStationBackgroundTaskClearMarshal(t *testing.T) {
+	r, err := FileStationBackgroundTaskClear{}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "clear_finished")
+	ensure.DeepEqual(t, r.Params, url.Values{})
+	r, err = FileStationBackgroundTaskClear{TaskIDs: []string{"a", "b"}}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Params, url.Values{"taskid": []string{"a,b"}})
+}
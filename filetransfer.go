@@ -0,0 +1,167 @@
+package syno
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/daaku/syno/params"
+)
+
+const (
+	fileUploadAPI     = "SYNO.FileStation.Upload"
+	fileUploadVersion = "2"
+
+	fileDownloadAPI     = "SYNO.FileStation.Download"
+	fileDownloadVersion = "2"
+)
+
+// FileStationUpload uploads a file to a folder. The Body is streamed as it is
+// sent, and can be wrapped using ProgressReader to report progress. The
+// response is FileUploadResponse.
+type FileStationUpload struct {
+	// Path is the folder to upload the file to.
+	Path     string
+	Filename string
+	Body     io.Reader
+
+	// CreateParents creates the folder and its parents if they do not exist.
+	CreateParents bool
+
+	Overwrite FileOverwriteMode
+
+	// Modified, Created and Accessed set the times of the uploaded file, and
+	// are left to the server if zero.
+	Modified time.Time
+	Created  time.Time
+	Accessed time.Time
+}
+
+// Validate checks that the folder, file name and body are given.
+func (f FileStationUpload) Validate() error {
+	if err := requireField("Path", f.Path); err != nil {
+		return err
+	}
+	if err := requireField("Filename", f.Filename); err != nil {
+		return err
+	}
+	if f.Body == nil {
+		return &ValidationError{Field: "Body", Reason: "required"}
+	}
+	if !f.Overwrite.Valid() {
+		return &ValidationError{Field: "Overwrite", Reason: "unknown mode"}
+	}
+	return nil
+}
+
+// setMillis sets the key to the time in milliseconds since the epoch if it is
+// not zero.
+func setMillis(p url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		params.SetInt64(p, key, t.UnixNano()/int64(time.Millisecond))
+	}
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationUpload) MarshalRequest() (*Request, error) {
+	p := url.Values{
+		"path":           []string{f.Path},
+		"create_parents": []string{strconv.FormatBool(f.CreateParents)},
+	}
+	switch f.Overwrite {
+	case FileOverwrite:
+		p.Set("overwrite", "true")
+	case FileOverwriteSkip:
+		p.Set("overwrite", "false")
+	}
+	setMillis(p, "mtime", f.Modified)
+	setMillis(p, "crtime", f.Created)
+	setMillis(p, "atime", f.Accessed)
+	return &Request{
+		Path:       fileStationPath,
+		API:        fileUploadAPI,
+		Version:    fileUploadVersion,
+		Method:     "upload",
+		HTTPMethod: http.MethodPost,
+		Params:     p,
+		Files:      []RequestFile{{Name: "file", Filename: f.Filename, Body: f.Body}},
+		Session:    SessionFileStation,
+	}, nil
+}
+
+// FileUploadResponse is the response for FileStationUpload. Skipped reports if
+// the file already existed and was skipped as requested by
+// FileOverwriteSkip.
+type FileUploadResponse struct {
+	File     string  `json:"file"`
+	Skipped  bool    `json:"blSkip"`
+	PID      int     `json:"pid"`
+	Progress float64 `json:"progress"`
+}
+
+// FileStationDownload downloads files. A single file is sent as is, while a
+// folder or several paths are sent as a zip archive. The response is a
+// Stream, so it must be made with DoStream or by passing a *Stream to Call.
+type FileStationDownload struct {
+	Paths []string
+
+	// Attachment asks the server to send the file for saving, instead of for
+	// opening it in a browser. The contents are the same either way.
+	Attachment bool
+}
+
+// Validate checks that at least one path is given.
+func (f FileStationDownload) Validate() error {
+	if len(f.Paths) == 0 {
+		return &ValidationError{Field: "Paths", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (f FileStationDownload) MarshalRequest() (*Request, error) {
+	p := url.Values{}
+	params.SetJSONArray(p, "path", f.Paths)
+	p.Set("mode", "open")
+	if f.Attachment {
+		p.Set("mode", "download")
+	}
+	return &Request{
+		Path:    fileStationPath,
+		API:     fileDownloadAPI,
+		Version: fileDownloadVersion,
+		Method:  "download",
+		Params:  p,
+		Session: SessionFileStation,
+	}, nil
+}
+
+// DownloadFile streams the file from the server without buffering it. The
+// Stream reports the size and name of the file, and must be closed once read.
+func (c *Client) DownloadFile(ctx context.Context, filePath string) (*Stream, error) {
+	var s Stream
+	if err := c.Call(ctx, FileStationDownload{Paths: []string{filePath}}, &s); err != nil {
+		return nil, err
+	}
+	if s.Filename == "" {
+		s.Filename = path.Base(filePath)
+	}
+	return &s, nil
+}
+
+// DownloadZip streams a zip archive of the given files and folders without
+// buffering it. The archive must be closed once read. The server only sends an
+// archive for a folder or several paths. A single path that is a regular file
+// is sent as is, as DownloadFile does.
+func (c *Client) DownloadZip(ctx context.Context, paths ...string) (io.ReadCloser, error) {
+	var s Stream
+	err := c.Call(ctx, FileStationDownload{Paths: paths, Attachment: true}, &s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,223 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestFileStationUploadMarshal(t *testing.T) {
+	body := strings.NewReader("a")
+	r, err := FileStationUpload{
+		Path:          "/video",
+		Filename:      "a.mkv",
+		Body:          body,
+		CreateParents: true,
+		Overwrite:     FileOverwriteSkip,
+		Modified:      time.Unix(1500000000, 5e6),
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       fileStationPath,
+		API:        fileUploadAPI,
+		Version:    fileUploadVersion,
+		Method:     "upload",
+		HTTPMethod: "POST",
+		Params: url.Values{
+			"path":           []string{"/video"},
+			"create_parents": []string{"true"},
+			"overwrite":      []string{"false"},
+			"mtime":          []string{"1500000000005"},
+		},
+		Files:   []RequestFile{{Name: "file", Filename: "a.mkv", Body: body}},
+		Session: SessionFileStation,
+	})
+}
+
+func TestFileStationUploadValidate(t *testing.T) {
+	cases := []struct {
+		Upload FileStationUpload
+		Err    error
+	}{
+		{
+			Upload: FileStationUpload{Filename: "a", Body: strings.NewReader("")},
+			Err:    &ValidationError{Field: "Path", Reason: "required"},
+		},
+		{
+			Upload: FileStationUpload{Path: "/a", Body: strings.NewReader("")},
+			Err:    &ValidationError{Field: "Filename", Reason: "required"},
+		},
+		{
+			Upload: FileStationUpload{Path: "/a", Filename: "a"},
+			Err:    &ValidationError{Field: "Body", Reason: "required"},
+		},
+		{
+			Upload: FileStationUpload{
+				Path:      "/a",
+				Filename:  "a",
+				Body:      strings.NewReader(""),
+				Overwrite: "always",
+			},
+			Err: &ValidationError{Field: "Overwrite", Reason: "unknown mode"},
+		},
+		{
+			Upload: FileStationUpload{
+				Path:     "/a",
+				Filename: "a",
+				Body:     strings.NewReader(""),
+			},
+		},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, c.Upload.Validate(), c.Err)
+	}
+}
+
+func TestClientFileStationUpload(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.Nil(t, r.ParseMultipartForm(1<<20))
+			ensure.DeepEqual(t, r.FormValue("api"), fileUploadAPI)
+			ensure.DeepEqual(t, r.FormValue("path"), "/video")
+			ensure.DeepEqual(t, r.FormValue("create_parents"), "false")
+			f, h, err := r.FormFile("file")
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, h.Filename, "a.txt")
+			b, err := ioutil.ReadAll(f)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, string(b), "hello")
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": true,
+					"data": {"blSkip": false, "file": "a.txt", "pid": 42, "progress": 1}
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	var res FileUploadResponse
+	err = c.Call(context.Background(), FileStationUpload{
+		Path:     "/video",
+		Filename: "a.txt",
+		Body:     strings.NewReader("hello"),
+	}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, FileUploadResponse{File: "a.txt", PID: 42, Progress: 1})
+}
+
+func TestFileStationDownloadMarshal(t *testing.T) {
+	r, err := FileStationDownload{Paths: []string{"/video/a.mkv"}, Attachment: true}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    fileStationPath,
+		API:     fileDownloadAPI,
+		Version: fileDownloadVersion,
+		Method:  "download",
+		Params: url.Values{
+			"path": []string{`["/video/a.mkv"]`},
+			"mode": []string{"download"},
+		},
+		Session: SessionFileStation,
+	})
+}
+
+func TestClientDownloadFile(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Content-Type":        []string{"application/octet-stream"},
+				"Content-Disposition": []string{`attachment; filename="b.txt"`},
+			},
+			ContentLength: 5,
+			Body:          ioutil.NopCloser(strings.NewReader("hello")),
+		}
+	})
+	s, err := c.DownloadFile(context.Background(), "/video/a.txt")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.Filename, "b.txt")
+	ensure.DeepEqual(t, s.ContentLength, int64(5))
+	b, err := ioutil.ReadAll(s)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), "hello")
+	ensure.Nil(t, s.Close())
+}
+
+func TestClientDownloadFileDefaultName(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       ioutil.NopCloser(strings.NewReader("hello")),
+		}
+	})
+	s, err := c.DownloadFile(context.Background(), "/video/a.txt")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.Filename, "a.txt")
+	ensure.Nil(t, s.Close())
+}
+
+func TestClientDownloadFileError(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success": false, "error": {"code": 408}}`)),
+		}
+	})
+	_, err := c.DownloadFile(context.Background(), "/video/a.txt")
+	ensure.True(t, errors.Is(err, ErrorFileNotExist), err)
+}
+
+func TestClientDownloadZip(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.FormValue("api"), fileDownloadAPI)
+			ensure.DeepEqual(t, r.FormValue("path"), `["/video/a","/video/b.mkv"]`)
+			ensure.DeepEqual(t, r.FormValue("mode"), "download")
+			return &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{"Content-Type": []string{"application/zip"}},
+				Body:       ioutil.NopCloser(strings.NewReader("PK")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	rc, err := c.DownloadZip(context.Background(), "/video/a", "/video/b.mkv")
+	ensure.Nil(t, err)
+	b, err := ioutil.ReadAll(rc)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), "PK")
+	ensure.Nil(t, rc.Close())
+}
+
+func TestClientDownloadZipSingleFile(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.FormValue("path"), `["/video/b.mkv"]`)
+			ensure.DeepEqual(t, r.FormValue("mode"), "download")
+			return &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{"Content-Type": []string{"video/x-matroska"}},
+				Body:       ioutil.NopCloser(strings.NewReader("mkv")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	rc, err := c.DownloadZip(context.Background(), "/video/b.mkv")
+	ensure.Nil(t, err)
+	b, err := ioutil.ReadAll(rc)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), "mkv")
+	ensure.Nil(t, rc.Close())
+}
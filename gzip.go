@@ -0,0 +1,51 @@
+package syno
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipBody decompresses a response body and closes the underlying body along
+// with the decompressor.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g *gzipBody) Close() error {
+	g.Reader.Close()
+	return g.body.Close()
+}
+
+// decompress replaces the body of a gzip encoded response with its
+// decompressed contents. The http.Transport only does so itself if it added
+// the Accept-Encoding header, which is not the case once it has been set on
+// the request.
+func decompress(hres *http.Response) error {
+	if !strings.EqualFold(hres.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(hres.Body)
+	if err != nil {
+		hres.Body.Close()
+		return err
+	}
+	hres.Body = &gzipBody{Reader: zr, body: hres.Body}
+	hres.Header.Del("Content-Encoding")
+	hres.Header.Del("Content-Length")
+	hres.ContentLength = -1
+	hres.Uncompressed = true
+	return nil
+}
+
+// ClientCompression configures whether the Client asks for gzip compressed
+// JSON responses, which it does by default. Compression substantially shrinks
+// large listings, but can be disabled for servers that mishandle it.
+func ClientCompression(enabled bool) ClientOption {
+	return func(c *Client) error {
+		c.disableCompression = !enabled
+		return nil
+	}
+}
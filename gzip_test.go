@@ -0,0 +1,91 @@
+package syno
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	_, err := w.Write([]byte(s))
+	ensure.Nil(t, err)
+	ensure.Nil(t, w.Close())
+	return b.Bytes()
+}
+
+func TestClientGzip(t *testing.T) {
+	body := gzipped(t, `{"success":true,"data":{"sid":"sid"}}`)
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("Accept-Encoding"), "gzip")
+			return &http.Response{
+				Header: http.Header{"Content-Encoding": []string{"gzip"}},
+				Body:   ioutil.NopCloser(bytes.NewReader(body)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	var res AuthLoginResponse
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, &res))
+	ensure.DeepEqual(t, res.SID, "sid")
+}
+
+func TestClientGzipInvalid(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Header: http.Header{"Content-Encoding": []string{"gzip"}},
+				Body:   ioutil.NopCloser(strings.NewReader(`{"success":true}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ensure.Err(
+		t,
+		c.Do(context.Background(), &Request{}, nil),
+		regexp.MustCompile("gzip: invalid header"),
+	)
+}
+
+func TestClientCompressionDisabled(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientCompression(false),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("Accept-Encoding"), "")
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{"success":true}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+}
+
+func TestClientStreamNotCompressed(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("Accept-Encoding"), "")
+			return &http.Response{
+				Header: http.Header{"Content-Type": []string{"image/png"}},
+				Body:   ioutil.NopCloser(strings.NewReader("png")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	s, err := c.DoStream(context.Background(), &Request{})
+	ensure.Nil(t, err)
+	ensure.Nil(t, s.Close())
+}
@@ -0,0 +1,64 @@
+package syno
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type headerContextKey struct{}
+
+type paramContextKey struct{}
+
+// WithHeader returns a context that adds the header to all requests made
+// with it, in addition to any already added by the parent context.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	h, _ := ctx.Value(headerContextKey{}).(http.Header)
+	h = h.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	h.Add(key, value)
+	return context.WithValue(ctx, headerContextKey{}, h)
+}
+
+// WithParam returns a context that sets the parameter on all requests made
+// with it, replacing the value a request may have for it.
+func WithParam(ctx context.Context, key, value string) context.Context {
+	p, _ := ctx.Value(paramContextKey{}).(url.Values)
+	c := make(url.Values, len(p)+1)
+	for k, v := range p {
+		c[k] = v
+	}
+	c.Set(key, value)
+	return context.WithValue(ctx, paramContextKey{}, c)
+}
+
+// withContextParams returns a copy of the request with the parameters set on
+// the context applied, or the request itself if there are none.
+func withContextParams(ctx context.Context, r *Request) *Request {
+	p, _ := ctx.Value(paramContextKey{}).(url.Values)
+	if len(p) == 0 {
+		return r
+	}
+	c := *r
+	c.Params = make(url.Values, len(r.Params)+len(p))
+	for k, v := range r.Params {
+		c.Params[k] = v
+	}
+	for k, v := range p {
+		c.Params[k] = v
+	}
+	return &c
+}
+
+// setHeaders sets the headers of the request and then those of the context
+// on the HTTP request, replacing any it already had.
+func setHeaders(ctx context.Context, hreq *http.Request, r *Request) {
+	h, _ := ctx.Value(headerContextKey{}).(http.Header)
+	for _, headers := range []http.Header{r.Header, h} {
+		for k, v := range headers {
+			hreq.Header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package syno
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func TestRequestHeaderAndContext(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("X-Request"), "r")
+			ensure.DeepEqual(t, r.Header.Values("X-Context"), []string{"a", "b"})
+			ensure.DeepEqual(t, r.Header.Get("X-Both"), "context")
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v.Get("foo"), "context")
+			ensure.DeepEqual(t, v.Get("bar"), "bar")
+			ensure.DeepEqual(t, v.Get("debug"), "true")
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ctx := WithHeader(context.Background(), "X-Context", "a")
+	ctx = WithHeader(ctx, "x-context", "b")
+	ctx = WithHeader(ctx, "X-Both", "context")
+	ctx = WithParam(ctx, "debug", "true")
+	ctx = WithParam(ctx, "foo", "context")
+	r := &Request{
+		Params: url.Values{
+			"foo": []string{"request"},
+			"bar": []string{"bar"},
+		},
+		Header: http.Header{
+			"X-Request": []string{"r"},
+			"X-Both":    []string{"request"},
+		},
+	}
+	ensure.Nil(t, c.Do(ctx, r, nil))
+	ensure.DeepEqual(t, r.Params.Get("foo"), "request")
+}
+
+func TestWithHeaderDoesNotModifyParent(t *testing.T) {
+	parent := WithHeader(context.Background(), "X-A", "a")
+	WithHeader(parent, "X-A", "b")
+	WithParam(WithParam(parent, "a", "a"), "b", "b")
+	ensure.DeepEqual(t, parent.Value(headerContextKey{}), http.Header{"X-A": []string{"a"}})
+	ensure.True(t, parent.Value(paramContextKey{}) == nil)
+}
@@ -0,0 +1,138 @@
+package syno
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/daaku/syno/params"
+)
+
+const (
+	apiInfoPath    = "/webapi/query.cgi"
+	apiInfoAPI     = "SYNO.API.Info"
+	apiInfoVersion = "1"
+)
+
+// APIInfo queries the APIs available on the server along with their paths and
+// supported versions. It does not require authentication. The response is
+// APIInfoResponse.
+type APIInfo struct {
+	// Query lists the APIs to describe. If empty, all APIs are described.
+	Query []string
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (a APIInfo) MarshalRequest() (*Request, error) {
+	p := url.Values{"query": []string{"all"}}
+	params.SetCSV(p, "query", a.Query)
+	return &Request{
+		Path:      apiInfoPath,
+		API:       apiInfoAPI,
+		Version:   apiInfoVersion,
+		Method:    "query",
+		Params:    p,
+		Cacheable: true,
+	}, nil
+}
+
+// APIInfoEntry describes an API available on the server.
+type APIInfoEntry struct {
+	Path          string `json:"path"`
+	MinVersion    int    `json:"minVersion"`
+	MaxVersion    int    `json:"maxVersion"`
+	RequestFormat string `json:"requestFormat"`
+}
+
+// APIInfoResponse is the response from an APIInfo request, keyed by API name.
+type APIInfoResponse map[string]APIInfoEntry
+
+// apiInfoCache lazily fetches and caches the APIInfoResponse for all APIs.
+type apiInfoCache struct {
+	mu   sync.Mutex
+	info APIInfoResponse
+}
+
+func (a *apiInfoCache) get(ctx context.Context, c *Client) (APIInfoResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.info != nil {
+		return a.info, nil
+	}
+	var info APIInfoResponse
+	if err := c.Call(ctx, APIInfo{}, &info); err != nil {
+		return nil, err
+	}
+	a.info = info
+	return info, nil
+}
+
+// negotiate returns the request with the Version, and the Path if one was not
+// specified, chosen based on the versions of the API the server supports. It
+// only applies to requests without a Version and with a MinVersion or
+// MaxVersion.
+func (c *Client) negotiate(ctx context.Context, r *Request) (*Request, error) {
+	if r.Version != "" || (r.MinVersion == 0 && r.MaxVersion == 0) {
+		return r, nil
+	}
+	n := *r
+	if v, ok := c.pinnedVersions[r.API]; ok {
+		n.Version = strconv.Itoa(v)
+		return &n, nil
+	}
+	info, err := c.apiInfo.get(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	e, ok := info[r.API]
+	if !ok {
+		return nil, fmt.Errorf("syno: API %s is not available", r.API)
+	}
+	min, max := e.MinVersion, e.MaxVersion
+	if r.MinVersion > min {
+		min = r.MinVersion
+	}
+	if r.MaxVersion != 0 && r.MaxVersion < max {
+		max = r.MaxVersion
+	}
+	if min > max {
+		return nil, fmt.Errorf(
+			"syno: API %s supports versions %d to %d, request requires %d to %d",
+			r.API, e.MinVersion, e.MaxVersion, r.MinVersion, r.MaxVersion)
+	}
+	n.Version = strconv.Itoa(max)
+	if n.Path == "" && e.Path != "" {
+		n.Path = "/webapi/" + e.Path
+	}
+	return &n, nil
+}
+
+// HasAPI reports if the server provides the API, based on the cached APIInfo
+// response. This allows picking between API families, such as the
+// SYNO.DownloadStation2 APIs that replace SYNO.DownloadStation in DSM 7.
+// Pinned APIs are assumed to be provided.
+func (c *Client) HasAPI(ctx context.Context, api string) (bool, error) {
+	if _, ok := c.pinnedVersions[api]; ok {
+		return true, nil
+	}
+	info, err := c.apiInfo.get(ctx, c)
+	if err != nil {
+		return false, err
+	}
+	_, ok := info[api]
+	return ok, nil
+}
+
+// ClientPinVersion pins the version used for the API, instead of negotiating
+// it for requests that specify a MinVersion or MaxVersion.
+func ClientPinVersion(api string, version int) ClientOption {
+	return func(c *Client) error {
+		if c.pinnedVersions == nil {
+			c.pinnedVersions = make(map[string]int)
+		}
+		c.pinnedVersions[api] = version
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func TestAPIInfoMarshal(t *testing.T) {
+	cases := []struct {
+		APIInfo APIInfo
+		Query   string
+	}{
+		{APIInfo: APIInfo{}, Query: "all"},
+		{APIInfo: APIInfo{Query: []string{"a", "b"}}, Query: "a,b"},
+	}
+	for _, c := range cases {
+		r, err := c.APIInfo.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:      apiInfoPath,
+			API:       apiInfoAPI,
+			Version:   apiInfoVersion,
+			Method:    "query",
+			Params:    url.Values{"query": []string{c.Query}},
+			Cacheable: true,
+		})
+	}
+}
+
+func TestAPIInfoResponseUnmarshal(t *testing.T) {
+	var res APIInfoResponse
+	err := json.Unmarshal([]byte(`{
+		"SYNO.API.Auth": {
+			"path": "auth.cgi",
+			"minVersion": 1,
+			"maxVersion": 6,
+			"requestFormat": "JSON"
+		}
+	}`), &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, APIInfoResponse{
+		"SYNO.API.Auth": {
+			Path:          "auth.cgi",
+			MinVersion:    1,
+			MaxVersion:    6,
+			RequestFormat: "JSON",
+		},
+	})
+}
+
+func apiInfoTransport(t *testing.T, queries *int, check func(url.Values)) transportFunc {
+	return transportFunc(func(r *http.Request) (*http.Response, error) {
+		v, err := url.ParseQuery(r.URL.RawQuery)
+		ensure.Nil(t, err)
+		if v.Get("api") == apiInfoAPI {
+			*queries++
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data": map[string]interface{}{
+						"SYNO.Foo": map[string]interface{}{
+							"path":       "foo.cgi",
+							"minVersion": 2,
+							"maxVersion": 5,
+						},
+					},
+				})),
+			}, nil
+		}
+		check(v)
+		ensure.DeepEqual(t, r.URL.Path, "/webapi/foo.cgi")
+		return &http.Response{
+			Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+				"success": true,
+			})),
+		}, nil
+	})
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	var queries int
+	var version string
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(apiInfoTransport(t, &queries, func(v url.Values) {
+			version = v.Get("version")
+		})),
+	)
+	ensure.Nil(t, err)
+	ctx := context.Background()
+	ensure.Nil(t, c.Do(ctx, &Request{API: "SYNO.Foo", MinVersion: 1}, nil))
+	ensure.DeepEqual(t, version, "5")
+	ensure.Nil(t, c.Do(ctx, &Request{API: "SYNO.Foo", MaxVersion: 3}, nil))
+	ensure.DeepEqual(t, version, "3")
+	ensure.Nil(t, c.Do(ctx, &Request{API: "SYNO.Foo", MinVersion: 3, MaxVersion: 9}, nil))
+	ensure.DeepEqual(t, version, "5")
+	ensure.DeepEqual(t, queries, 1)
+}
+
+func TestNegotiateVersionUnsupported(t *testing.T) {
+	var queries int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(apiInfoTransport(t, &queries, nil)),
+	)
+	ensure.Nil(t, err)
+	ctx := context.Background()
+	err = c.Do(ctx, &Request{API: "SYNO.Foo", MinVersion: 6}, nil)
+	ensure.Err(t, err, regexp.MustCompile("supports versions 2 to 5, request requires 6 to 0"))
+	err = c.Do(ctx, &Request{API: "SYNO.Bar", MinVersion: 1}, nil)
+	ensure.Err(t, err, regexp.MustCompile("API SYNO.Bar is not available"))
+}
+
+func TestNegotiateVersionPinned(t *testing.T) {
+	var queries int
+	var version string
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientPinVersion("SYNO.Foo", 4),
+		ClientTransport(apiInfoTransport(t, &queries, func(v url.Values) {
+			version = v.Get("version")
+		})),
+	)
+	ensure.Nil(t, err)
+	r := &Request{Path: "/webapi/foo.cgi", API: "SYNO.Foo", MinVersion: 1}
+	ensure.Nil(t, c.Do(context.Background(), r, nil))
+	ensure.DeepEqual(t, version, "4")
+	ensure.DeepEqual(t, queries, 0)
+}
+
+func TestNegotiateVersionInfoError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, givenErr
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{API: "SYNO.Foo", MinVersion: 1}, nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientHasAPI(t *testing.T) {
+	var queries int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientPinVersion("SYNO.Pinned", 1),
+		ClientTransport(apiInfoTransport(t, &queries, nil)),
+	)
+	ensure.Nil(t, err)
+	ctx := context.Background()
+	ok, err := c.HasAPI(ctx, "SYNO.Pinned")
+	ensure.Nil(t, err)
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, queries, 0)
+	ok, err = c.HasAPI(ctx, "SYNO.Foo")
+	ensure.Nil(t, err)
+	ensure.True(t, ok)
+	ok, err = c.HasAPI(ctx, "SYNO.Bar")
+	ensure.Nil(t, err)
+	ensure.False(t, ok)
+	ensure.DeepEqual(t, queries, 1)
+}
@@ -0,0 +1,77 @@
+package syno
+
+import (
+	"context"
+	"time"
+)
+
+// startKeepAlive starts the keep-alive goroutine, which runs until Close.
+func (c *Client) startKeepAlive(interval time.Duration) {
+	c.stopKeepAlive = make(chan struct{})
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-c.stopKeepAlive:
+				return
+			case <-t.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				c.ping(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// ping makes a request using every session held by the Client to keep them
+// from expiring, logging in again if one already has and credentials are
+// configured.
+func (c *Client) ping(ctx context.Context) {
+	c.mu.Lock()
+	sid := c.sid
+	sessions := make(map[string]string, len(c.sessions))
+	for session, s := range c.sessions {
+		sessions[session] = s
+	}
+	c.mu.Unlock()
+
+	alive := func(session, sid string) bool {
+		r, _ := pingRequest(session).MarshalRequest()
+		r.SID = sid
+		r.Cacheable = false
+		return !isSessionError(c.Do(ctx, r, nil))
+	}
+	if sid != "" && !alive("", sid) && c.credentials != nil {
+		c.Login(ctx)
+	}
+	for session, s := range sessions {
+		if !alive(session, s) && c.credentials != nil {
+			c.LoginSession(ctx, session)
+		}
+	}
+}
+
+// pingRequest returns the lightweight request used to keep the session
+// alive. It must need authentication, as only those refresh the session and
+// fail once it has expired.
+func pingRequest(session string) MarshalRequest {
+	switch session {
+	case SessionDownloadStation:
+		return DownloadStationGetInfo{}
+	case SessionSurveillanceStation:
+		return SurveillanceHomeModeGetInfo{}
+	}
+	return FileStationInfo{}
+}
+
+// ClientKeepAlive configures the Client to periodically make a lightweight
+// request using its sessions, so they do not expire on an idle DSM. Expired
+// sessions are logged into again if credentials are configured. The
+// keep-alive runs until Close is called.
+func ClientKeepAlive(interval time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.keepAlive = interval
+		return nil
+	}
+}
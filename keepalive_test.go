@@ -0,0 +1,111 @@
+package syno
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func TestClientKeepAlive(t *testing.T) {
+	var mu sync.Mutex
+	pings := map[string]int{}
+	pinged := make(chan struct{}, 100)
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("sid"),
+		ClientKeepAlive(time.Millisecond),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			if v.Get("api") == fileInfoAPI {
+				mu.Lock()
+				pings[v.Get("_sid")]++
+				mu.Unlock()
+				pinged <- struct{}{}
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	c.storeLogin(SessionFileStation, false, &AuthLoginResponse{SID: "fs"})
+	for i := 0; i < 4; i++ {
+		<-pinged
+	}
+	ensure.Nil(t, c.Close(context.Background()))
+	ensure.Nil(t, c.Close(context.Background()))
+	mu.Lock()
+	defer mu.Unlock()
+	ensure.True(t, pings["sid"] > 0)
+}
+
+func TestClientKeepAliveRelogin(t *testing.T) {
+	st := &sessionTransport{t: t, valid: "valid"}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(st),
+		ClientSID("expired"),
+		ClientCredentials(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	c.storeLogin(SessionFileStation, false, &AuthLoginResponse{SID: "expired"})
+	c.ping(context.Background())
+	ensure.DeepEqual(t, st.logins, 2)
+	ensure.DeepEqual(t, c.SID(), "sid1")
+	ensure.DeepEqual(t, c.sessions[SessionFileStation], "sid2")
+}
+
+func TestClientKeepAliveAlive(t *testing.T) {
+	st := &sessionTransport{t: t, valid: "valid"}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(st),
+		ClientSID("valid"),
+		ClientCredentials(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	c.ping(context.Background())
+	ensure.DeepEqual(t, st.logins, 0)
+	ensure.DeepEqual(t, st.calls, 1)
+}
+
+func TestClientKeepAlivePingExpired(t *testing.T) {
+	var logins int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("expired"),
+		ClientCredentials(AuthLogin{Account: "a", Password: "b"}),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			res := map[string]interface{}{"success": true}
+			switch v.Get("api") {
+			case authLoginAPI:
+				logins++
+				res["data"] = map[string]string{"sid": "fresh"}
+			case fileInfoAPI:
+				ensure.DeepEqual(t, v.Get("_sid"), "expired")
+				res = map[string]interface{}{
+					"error": map[string]interface{}{"code": ErrorSessionTimeout},
+				}
+			default:
+				t.Fatalf("unexpected ping api: %s", v.Get("api"))
+			}
+			return &http.Response{Body: ioutil.NopCloser(jsonpipe.Encode(res))}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	c.ping(context.Background())
+	ensure.DeepEqual(t, logins, 1)
+	ensure.DeepEqual(t, c.SID(), "fresh")
+}
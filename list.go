@@ -0,0 +1,237 @@
+package syno
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ListResponse is the response shape shared by list style APIs, which return
+// a page of items along with the total number of items available. The items
+// are decoded from the array the API returns them in, such as "tasks" for
+// DownloadStation or "files" for FileStation. Arrays in the fields named by
+// listItemKeys are used over any other, and otherwise the first array valued
+// field is used.
+type ListResponse[T any] struct {
+	Total  int
+	Offset int
+	Items  []T
+}
+
+// listItemKeys are the fields list style APIs return their items in. Other
+// array valued fields in the same response, such as metadata, are ignored if
+// one of these is present.
+var listItemKeys = map[string]bool{
+	"bookmarks":  true,
+	"events":     true,
+	"feeds":      true,
+	"files":      true,
+	"folders":    true,
+	"items":      true,
+	"patrols":    true,
+	"presets":    true,
+	"recordings": true,
+	"shares":     true,
+	"sites":      true,
+	"task":       true,
+	"tasks":      true,
+}
+
+// UnmarshalJSON decodes the response, taking the Items from the array valued
+// field chosen as CallEach does.
+func (l *ListResponse[T]) UnmarshalJSON(b []byte) error {
+	var items []T
+	e := &listEach[T]{f: func(item T) error {
+		items = append(items, item)
+		return nil
+	}}
+	if err := e.decodeData(json.NewDecoder(bytes.NewReader(b))); err != nil {
+		return err
+	}
+	*l = e.list
+	if e.found {
+		l.Items = items
+		if l.Items == nil {
+			l.Items = []T{}
+		}
+	}
+	return nil
+}
+
+// DownloadTaskListResponse is the response from a DownloadTaskList request.
+type DownloadTaskListResponse = ListResponse[DownloadTask]
+
+// dataDecoder is implemented by the data given to Do to decode the "data" of
+// the response as it is read, rather than after buffering all of it.
+type dataDecoder interface {
+	decodeData(dec *json.Decoder) error
+}
+
+// decodeStreaming decodes the response envelope, handing the "data" to d as
+// it is read.
+func decodeStreaming(r io.Reader, d dataDecoder) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	var res Response
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "success":
+			err = dec.Decode(&res.Success)
+		case "error":
+			err = dec.Decode(&res.Error)
+		case "data":
+			err = d.decodeData(dec)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if !res.Success {
+		return res.Error.err()
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != want {
+		return fmt.Errorf("syno: unexpected JSON %v, expected %v", t, want)
+	}
+	return nil
+}
+
+// listEach decodes a list response, calling f for each item instead of
+// collecting them. Items in a field named by listItemKeys are decoded as they
+// are read. The first other array is kept until the end of the data, and its
+// items are only used if there was no such field.
+type listEach[T any] struct {
+	list     ListResponse[T]
+	f        func(T) error
+	found    bool
+	fallback json.RawMessage
+}
+
+func (l *listEach[T]) decodeData(dec *json.Decoder) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		k, _ := key.(string)
+		switch {
+		case k == "total":
+			err = dec.Decode(&l.list.Total)
+		case k == "offset":
+			err = dec.Decode(&l.list.Offset)
+		case listItemKeys[k] && !l.found:
+			l.found, err = l.decodeItems(dec)
+		default:
+			var v json.RawMessage
+			err = dec.Decode(&v)
+			if l.fallback == nil && bytes.HasPrefix(bytes.TrimSpace(v), []byte("[")) {
+				l.fallback = v
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if !l.found && l.fallback != nil {
+		var err error
+		l.found, err = l.decodeItems(json.NewDecoder(bytes.NewReader(l.fallback)))
+		return err
+	}
+	return nil
+}
+
+// decodeItems calls f for each item of the array value, reporting false if
+// the value is not an array.
+func (l *listEach[T]) decodeItems(dec *json.Decoder) (bool, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	if d, ok := t.(json.Delim); !ok || d != '[' {
+		if ok {
+			// Skip the rest of a nested object or array that is not a list.
+			for depth := 1; depth > 0; {
+				if t, err = dec.Token(); err != nil {
+					return false, err
+				}
+				if d, ok := t.(json.Delim); ok {
+					if d == '{' || d == '[' {
+						depth++
+					} else {
+						depth--
+					}
+				}
+			}
+		}
+		return false, nil
+	}
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return true, err
+		}
+		if err := l.f(item); err != nil {
+			return true, &callbackError{err: err}
+		}
+	}
+	return true, expectDelim(dec, ']')
+}
+
+// callbackError marks errors returned by the callback given to CallEach, so
+// they are returned as is.
+type callbackError struct {
+	err error
+}
+
+func (e *callbackError) Error() string { return e.err.Error() }
+
+func (e *callbackError) Unwrap() error { return e.err }
+
+// CallEach makes a list request like CallTyped, but decodes the items as the
+// response is read and calls f with each one, so memory use does not grow
+// with the size of the list. The returned ListResponse has the Total and
+// Offset but no Items. If f returns an error, decoding stops and the error is
+// returned. If the request is retried, f may be called again for the same
+// items.
+func CallEach[T any](
+	ctx context.Context,
+	c *Client,
+	r MarshalRequest,
+	f func(T) error,
+) (ListResponse[T], error) {
+	l := &listEach[T]{f: f}
+	err := c.Call(ctx, r, l)
+	var ce *callbackError
+	if errors.As(err, &ce) {
+		err = ce.err
+	}
+	return l.list, err
+}
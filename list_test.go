@@ -0,0 +1,144 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestListResponseUnmarshal(t *testing.T) {
+	var res DownloadTaskListResponse
+	err := json.Unmarshal([]byte(`{
+		"offset": 1,
+		"total": 3,
+		"tasks": [{"id": "dbid_1", "title": "a", "size": 42, "status": "finished"}]
+	}`), &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, DownloadTaskListResponse{
+		Total:  3,
+		Offset: 1,
+		Items: []DownloadTask{
+			{ID: "dbid_1", Title: "a", Size: 42, Status: "finished"},
+		},
+	})
+}
+
+func TestListResponseUnmarshalTwoArrays(t *testing.T) {
+	cases := []struct {
+		JSON  string
+		Items []string
+	}{
+		{`{"errors": ["x"], "files": ["a", "b"], "total": 2}`, []string{"a", "b"}},
+		{`{"files": ["a", "b"], "errors": ["x"], "total": 2}`, []string{"a", "b"}},
+		{`{"foo": ["a"], "bar": ["x"], "total": 1}`, []string{"a"}},
+	}
+	for _, c := range cases {
+		var res ListResponse[string]
+		ensure.Nil(t, json.Unmarshal([]byte(c.JSON), &res), c.JSON)
+		ensure.DeepEqual(t, res.Items, c.Items, c.JSON)
+
+		var items []string
+		_, err := CallEach(context.Background(),
+			listClient(t, `{"success":true,"data":`+c.JSON+`}`),
+			DownloadTaskList{},
+			func(item string) error {
+				items = append(items, item)
+				return nil
+			})
+		ensure.Nil(t, err, c.JSON)
+		ensure.DeepEqual(t, items, c.Items, c.JSON)
+	}
+}
+
+func TestListResponseUnmarshalEmpty(t *testing.T) {
+	var res ListResponse[string]
+	ensure.Nil(t, json.Unmarshal([]byte(`{"files":[],"total":0}`), &res))
+	ensure.DeepEqual(t, res, ListResponse[string]{Items: []string{}})
+}
+
+func TestListResponseUnmarshalError(t *testing.T) {
+	var res ListResponse[string]
+	ensure.NotNil(t, json.Unmarshal([]byte(`[]`), &res))
+	ensure.NotNil(t, json.Unmarshal([]byte(`{"total":"x"}`), &res))
+	ensure.NotNil(t, json.Unmarshal([]byte(`{"offset":"x"}`), &res))
+	ensure.NotNil(t, json.Unmarshal([]byte(`{"files":[1]}`), &res))
+}
+
+func listClient(t *testing.T, body string) *Client {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+func TestCallEach(t *testing.T) {
+	c := listClient(t, `{
+		"data": {
+			"offset": 2,
+			"extra": {"nested": [1, {"a": []}]},
+			"flag": true,
+			"tasks": [{"id": "a"}, {"id": "b"}],
+			"total": 10
+		},
+		"ignored": [1],
+		"success": true
+	}`)
+	var ids []string
+	res, err := CallEach(context.Background(), c, DownloadTaskList{},
+		func(task DownloadTask) error {
+			ids = append(ids, task.ID)
+			return nil
+		})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, ids, []string{"a", "b"})
+	ensure.DeepEqual(t, res, DownloadTaskListResponse{Total: 10, Offset: 2})
+}
+
+func TestCallEachCallbackError(t *testing.T) {
+	c := listClient(t, `{"data":{"tasks":[{"id":"a"},{"id":"b"}]},"success":true}`)
+	givenErr := ErrorSessionTimeout
+	var calls int
+	_, err := CallEach(context.Background(), c, DownloadTaskList{},
+		func(DownloadTask) error {
+			calls++
+			return givenErr
+		})
+	ensure.DeepEqual(t, err, givenErr)
+	ensure.DeepEqual(t, calls, 1)
+}
+
+func TestCallEachAPIError(t *testing.T) {
+	c := listClient(t, `{"error":{"code":105},"success":false}`)
+	_, err := CallEach(context.Background(), c, DownloadTaskList{},
+		func(DownloadTask) error { return nil })
+	ensure.True(t, errors.Is(err, ErrorPermissionDenied))
+}
+
+func TestCallEachInvalid(t *testing.T) {
+	bodies := []string{
+		`[]`,
+		`{"data":[]}`,
+		`{"data":{"tasks":[1]}}`,
+		`{"data":{"tasks":[{}}`,
+		`{"data":{"total":"x"}}`,
+		`{"success":"x"}`,
+		`{"success":true`,
+	}
+	for _, body := range bodies {
+		_, err := CallEach(context.Background(), listClient(t, body), DownloadTaskList{},
+			func(DownloadTask) error { return nil })
+		ensure.NotNil(t, err, body)
+	}
+}
@@ -0,0 +1,44 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// logRequest emits a structured event for a completed Do call. Successful
+// calls are logged at the debug level, and failed ones at the warn level.
+func (c *Client) logRequest(
+	ctx context.Context,
+	r *Request,
+	d time.Duration,
+	err error,
+) {
+	attrs := []slog.Attr{
+		slog.String("api", r.API),
+		slog.String("method", r.Method),
+		slog.String("version", r.Version),
+		slog.Duration("duration", d),
+	}
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+		var code Error
+		if errors.As(err, &code) {
+			attrs = append(attrs, slog.Int("code", int(code)))
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.logger.LogAttrs(ctx, level, "syno: request", attrs...)
+}
+
+// ClientLogger configures a logger which receives a structured event for
+// every request made by the Client, with the API, method, version, duration
+// and error code if any.
+func ClientLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) error {
+		c.logger = l
+		return nil
+	}
+}
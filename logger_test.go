@@ -0,0 +1,69 @@
+package syno
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func testLoggerClient(t *testing.T, res interface{}) (*Client, *bytes.Buffer) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientLogger(logger),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(res)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c, &out
+}
+
+func TestClientLoggerSuccess(t *testing.T) {
+	c, out := testLoggerClient(t, map[string]interface{}{"success": true})
+	err := c.Do(context.Background(), &Request{
+		API:     "api",
+		Method:  "method",
+		Version: "1",
+	}, nil)
+	ensure.Nil(t, err)
+	var event map[string]interface{}
+	ensure.Nil(t, json.Unmarshal(out.Bytes(), &event))
+	ensure.Subset(t, event, map[string]interface{}{
+		"level":   "DEBUG",
+		"msg":     "syno: request",
+		"api":     "api",
+		"method":  "method",
+		"version": "1",
+	})
+	_, ok := event["duration"]
+	ensure.True(t, ok)
+}
+
+func TestClientLoggerError(t *testing.T) {
+	c, out := testLoggerClient(t, map[string]interface{}{
+		"error": map[string]interface{}{"code": ErrorInvalidAPI},
+	})
+	err := c.Do(context.Background(), &Request{API: "api"}, nil)
+	ensure.True(t, errors.Is(err, ErrorInvalidAPI))
+	var event map[string]interface{}
+	ensure.Nil(t, json.Unmarshal(out.Bytes(), &event))
+	ensure.Subset(t, event, map[string]interface{}{
+		"level": "WARN",
+		"code":  float64(102),
+		"error": "syno: api: invalid API (102)",
+	})
+}
@@ -0,0 +1,119 @@
+package syno
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/daaku/syno/params"
+)
+
+// MarshalParams builds the request parameters from the fields of the given
+// struct, or pointer to one, that have a "syno" tag. The tag specifies the
+// parameter name followed by comma separated options:
+//
+//	omitempty  skips the parameter if the field has its zero value
+//	yesno      encodes bools as "yes" and "no" instead of "true" and "false"
+//	json       encodes the value as JSON, such as for arrays of paths
+//
+// Strings, bools, integers and floats are encoded as text. Slices are joined
+// with commas unless the json option is given. Nil pointers are always
+// skipped, other pointers are encoded as the value they point to.
+func MarshalParams(v interface{}) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("syno: cannot marshal params from %s", rv.Type())
+	}
+
+	p := url.Values{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, ok := f.Tag.Lookup("syno")
+		if !ok || tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		name := opts[0]
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		var omitEmpty, yesNo, asJSON bool
+		for _, o := range opts[1:] {
+			switch o {
+			case "omitempty":
+				omitEmpty = true
+			case "yesno":
+				yesNo = true
+			case "json":
+				asJSON = true
+			}
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		} else if omitEmpty && fv.IsZero() {
+			continue
+		}
+		if omitEmpty && fv.Kind() == reflect.Slice && fv.Len() == 0 {
+			continue
+		}
+
+		if asJSON {
+			if err := params.SetJSON(p, name, fv.Interface()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		s, err := formatParam(fv, yesNo)
+		if err != nil {
+			return nil, fmt.Errorf("syno: field %s: %w", f.Name, err)
+		}
+		p.Set(name, s)
+	}
+	return p, nil
+}
+
+func formatParam(v reflect.Value, yesNo bool) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		if yesNo {
+			if v.Bool() {
+				return "yes", nil
+			}
+			return "no", nil
+		}
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			s, err := formatParam(v.Index(i), yesNo)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, ","), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
@@ -0,0 +1,91 @@
+package syno
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestMarshalParams(t *testing.T) {
+	type params struct {
+		String   string   `syno:"string,omitempty"`
+		Int      int      `syno:"int,omitempty"`
+		Uint     uint8    `syno:"uint"`
+		Float    float64  `syno:"float,omitempty"`
+		Bool     bool     `syno:"bool"`
+		YesNo    bool     `syno:"yes_no,yesno"`
+		Slice    []string `syno:"slice,omitempty"`
+		Ints     []int    `syno:"ints,omitempty"`
+		JSON     []string `syno:"json,omitempty,json"`
+		Ptr      *bool    `syno:"ptr"`
+		Default  string   `syno:",omitempty"`
+		Skipped  string   `syno:"-"`
+		Untagged string
+	}
+	yes := true
+	cases := []struct {
+		Value  interface{}
+		Params url.Values
+	}{
+		{
+			Value: params{},
+			Params: url.Values{
+				"uint":   []string{"0"},
+				"bool":   []string{"false"},
+				"yes_no": []string{"no"},
+			},
+		},
+		{
+			Value: &params{
+				String:   "a",
+				Int:      -1,
+				Uint:     2,
+				Float:    1.5,
+				Bool:     true,
+				YesNo:    true,
+				Slice:    []string{"a", "b"},
+				Ints:     []int{1, 2},
+				JSON:     []string{"/a", "/b"},
+				Ptr:      &yes,
+				Default:  "d",
+				Skipped:  "s",
+				Untagged: "u",
+			},
+			Params: url.Values{
+				"string":  []string{"a"},
+				"int":     []string{"-1"},
+				"uint":    []string{"2"},
+				"float":   []string{"1.5"},
+				"bool":    []string{"true"},
+				"yes_no":  []string{"yes"},
+				"slice":   []string{"a,b"},
+				"ints":    []string{"1,2"},
+				"json":    []string{`["/a","/b"]`},
+				"ptr":     []string{"true"},
+				"default": []string{"d"},
+			},
+		},
+		{
+			Value:  (*params)(nil),
+			Params: url.Values{},
+		},
+	}
+	for _, c := range cases {
+		p, err := MarshalParams(c.Value)
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, p, c.Params)
+	}
+}
+
+func TestMarshalParamsErrors(t *testing.T) {
+	_, err := MarshalParams("foo")
+	ensure.Err(t, err, regexp.MustCompile("cannot marshal params from string"))
+
+	type params struct {
+		Map map[string]string `syno:"map"`
+	}
+	_, err = MarshalParams(params{})
+	ensure.Err(t, err, regexp.MustCompile("field Map: unsupported type"))
+}
@@ -0,0 +1,43 @@
+package syno
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Metrics receives measurements for the requests made by a Client, allowing
+// them to be exported to Prometheus or any other monitoring system.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// RequestStarted is called before a request is made, and is useful for
+	// tracking in-flight requests.
+	RequestStarted(api, method string)
+
+	// RequestFinished is called once a request completes, including any
+	// retries. MetricsCode provides a low cardinality label for the error.
+	RequestFinished(api, method string, d time.Duration, err error)
+}
+
+// MetricsCode returns a label describing the outcome of a request suitable for
+// use in metrics: "ok" for success, the numeric code for API errors, and
+// "error" for everything else.
+func MetricsCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	var code Error
+	if errors.As(err, &code) {
+		return strconv.Itoa(int(code))
+	}
+	return "error"
+}
+
+// ClientMetrics configures the Metrics that receive measurements for every
+// request made by the Client.
+func ClientMetrics(m Metrics) ClientOption {
+	return func(c *Client) error {
+		c.metrics = m
+		return nil
+	}
+}
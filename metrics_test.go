@@ -0,0 +1,57 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+type recordMetrics struct {
+	started  []string
+	finished []string
+	inFlight int
+}
+
+func (m *recordMetrics) RequestStarted(api, method string) {
+	m.inFlight++
+	m.started = append(m.started, api+"."+method)
+}
+
+func (m *recordMetrics) RequestFinished(api, method string, d time.Duration, err error) {
+	m.inFlight--
+	m.finished = append(m.finished, api+"."+method+":"+MetricsCode(err))
+}
+
+func TestClientMetrics(t *testing.T) {
+	m := &recordMetrics{}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientMetrics(m),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, m.inFlight, 1)
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"error": map[string]interface{}{"code": ErrorPermissionDenied},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{API: "a", Method: "m"}, nil)
+	ensure.True(t, errors.Is(err, ErrorPermissionDenied))
+	ensure.DeepEqual(t, m.inFlight, 0)
+	ensure.DeepEqual(t, m.started, []string{"a.m"})
+	ensure.DeepEqual(t, m.finished, []string{"a.m:105"})
+}
+
+func TestMetricsCode(t *testing.T) {
+	ensure.DeepEqual(t, MetricsCode(nil), "ok")
+	ensure.DeepEqual(t, MetricsCode(ErrorUnknown), "100")
+	ensure.DeepEqual(t, MetricsCode(errors.New("")), "error")
+}
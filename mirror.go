@@ -0,0 +1,271 @@
+package syno
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+)
+
+// MirrorOp is the kind of change made by Mirror.
+type MirrorOp string
+
+const (
+	MirrorMkdir  = MirrorOp("mkdir")
+	MirrorUpload = MirrorOp("upload")
+	MirrorDelete = MirrorOp("delete")
+)
+
+func (o MirrorOp) String() string { return string(o) }
+
+// MirrorChange is a change made, or in a dry run one that would be made, by
+// Mirror. Path is the path on the server.
+type MirrorChange struct {
+	Op   MirrorOp
+	Path string
+}
+
+// MirrorOptions configures Mirror.
+type MirrorOptions struct {
+	// CompareMD5 compares the MD5 of files that have the same size but a
+	// different modification time, and skips them if they match. Otherwise
+	// they are uploaded again.
+	CompareMD5 bool
+
+	// PollInterval is how often the MD5 of a file on the server is polled
+	// for. If zero, one second is used.
+	PollInterval time.Duration
+
+	// Delete removes the files and folders on the server that do not exist
+	// in the source.
+	Delete bool
+
+	// DryRun reports the changes without making them.
+	DryRun bool
+}
+
+func (o MirrorOptions) pollInterval() time.Duration {
+	if o.PollInterval <= 0 {
+		return time.Second
+	}
+	return o.PollInterval
+}
+
+// Mirror makes the folder on the server a copy of the source tree, such as
+// one from os.DirFS, uploading only the files that are missing or differ in
+// size or modification time. Uploaded files keep their modification time, so
+// an unchanged file is skipped by the next Mirror. The changes are returned
+// in the order they were made, including those made before an error. Files
+// on the server that are replaced by a folder, or the other way around, are
+// deleted even if Delete is not set.
+func (c *Client) Mirror(
+	ctx context.Context,
+	src fs.FS,
+	dest string,
+	o MirrorOptions,
+) ([]MirrorChange, error) {
+	dest = path.Clean(dest)
+	m := mirror{c: c, src: src, o: o}
+	exists, err := m.exists(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		parent, name := path.Split(dest)
+		if err := m.mkdir(ctx, path.Clean(parent), name); err != nil {
+			return m.changes, err
+		}
+	}
+	err = m.dir(ctx, ".", dest, exists)
+	return m.changes, err
+}
+
+type mirror struct {
+	c       *Client
+	src     fs.FS
+	o       MirrorOptions
+	changes []MirrorChange
+}
+
+// exists reports if the folder exists on the server.
+func (m *mirror) exists(ctx context.Context, dir string) (bool, error) {
+	err := m.c.Call(ctx, FileStationList{FolderPath: dir, Limit: 1}, nil)
+	if errors.Is(err, ErrorFileNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// dir mirrors the source folder into the server folder, which is only listed
+// if it exists.
+func (m *mirror) dir(ctx context.Context, srcDir, destDir string, exists bool) error {
+	entries, err := fs.ReadDir(m.src, srcDir)
+	if err != nil {
+		return err
+	}
+	remote := map[string]File{}
+	if exists {
+		res, err := CallTyped[FileListResponse](ctx, m.c, FileStationList{
+			FolderPath: destDir,
+			Additional: []string{FileAdditionalSize, FileAdditionalTime},
+		})
+		if err != nil {
+			return err
+		}
+		for _, f := range res.Items {
+			remote[f.Name] = f
+		}
+	}
+
+	for _, e := range entries {
+		srcPath := path.Join(srcDir, e.Name())
+		destPath := path.Join(destDir, e.Name())
+		r, ok := remote[e.Name()]
+		delete(remote, e.Name())
+		if ok && r.IsDir != e.IsDir() {
+			if err := m.delete(ctx, destPath); err != nil {
+				return err
+			}
+			ok = false
+		}
+
+		if e.IsDir() {
+			if !ok {
+				if err := m.mkdir(ctx, destDir, e.Name()); err != nil {
+					return err
+				}
+			}
+			if err := m.dir(ctx, srcPath, destPath, ok); err != nil {
+				return err
+			}
+			continue
+		}
+		if !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		if ok {
+			same, err := m.same(ctx, srcPath, destPath, info, r)
+			if err != nil {
+				return err
+			}
+			if same {
+				continue
+			}
+		}
+		if err := m.upload(ctx, srcPath, destDir, info); err != nil {
+			return err
+		}
+	}
+
+	if m.o.Delete {
+		extra := make([]string, 0, len(remote))
+		for _, r := range remote {
+			extra = append(extra, r.Path)
+		}
+		sort.Strings(extra)
+		for _, p := range extra {
+			if err := m.delete(ctx, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// same reports if the file on the server is the same as the source file.
+func (m *mirror) same(
+	ctx context.Context,
+	srcPath, destPath string,
+	info fs.FileInfo,
+	r File,
+) (bool, error) {
+	if r.Additional == nil || int64(r.Additional.Size) != info.Size() {
+		return false, nil
+	}
+	if r.Additional.Time != nil &&
+		r.Additional.Time.Modified.Unix() == info.ModTime().Unix() {
+		return true, nil
+	}
+	if !m.o.CompareMD5 {
+		return false, nil
+	}
+	local, err := m.md5(srcPath)
+	if err != nil {
+		return false, err
+	}
+	remote, err := m.c.FileMD5(ctx, destPath, m.o.pollInterval())
+	if err != nil {
+		return false, err
+	}
+	return local == remote, nil
+}
+
+func (m *mirror) md5(srcPath string) (string, error) {
+	f, err := m.src.Open(srcPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (m *mirror) mkdir(ctx context.Context, parent, name string) error {
+	m.changes = append(m.changes, MirrorChange{
+		Op:   MirrorMkdir,
+		Path: path.Join(parent, name),
+	})
+	if m.o.DryRun {
+		return nil
+	}
+	return m.c.Call(ctx, FileStationCreateFolder{
+		FolderPaths: []string{parent},
+		Names:       []string{name},
+		ForceParent: true,
+	}, nil)
+}
+
+func (m *mirror) upload(ctx context.Context, srcPath, destDir string, info fs.FileInfo) error {
+	m.changes = append(m.changes, MirrorChange{
+		Op:   MirrorUpload,
+		Path: path.Join(destDir, info.Name()),
+	})
+	if m.o.DryRun {
+		return nil
+	}
+	f, err := m.src.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.c.Call(ctx, FileStationUpload{
+		Path:      destDir,
+		Filename:  info.Name(),
+		Body:      f,
+		Overwrite: FileOverwrite,
+		Modified:  info.ModTime(),
+	}, nil)
+}
+
+func (m *mirror) delete(ctx context.Context, destPath string) error {
+	m.changes = append(m.changes, MirrorChange{Op: MirrorDelete, Path: destPath})
+	if m.o.DryRun {
+		return nil
+	}
+	return m.c.Call(ctx, FileStationDelete{
+		Paths:     []string{destPath},
+		Recursive: true,
+	}, nil)
+}
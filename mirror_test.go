@@ -0,0 +1,115 @@
+package syno
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func mirrorClient(t *testing.T, calls *[]string) *Client {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+				ensure.Nil(t, r.ParseMultipartForm(1<<20))
+			} else {
+				ensure.Nil(t, r.ParseForm())
+			}
+			api, folder := r.FormValue("api"), r.FormValue("folder_path")
+			data := "null"
+			switch api {
+			case fileListAPI:
+				switch folder {
+				case "/dest":
+					data = `{"total": 4, "files": [
+						{"name": "same.txt", "path": "/dest/same.txt",
+						 "additional": {"size": 3, "time": {"mtime": 1500000000}}},
+						{"name": "changed.txt", "path": "/dest/changed.txt",
+						 "additional": {"size": 3, "time": {"mtime": 1500000000}}},
+						{"name": "extra.txt", "path": "/dest/extra.txt",
+						 "additional": {"size": 1}},
+						{"name": "old", "path": "/dest/old", "isdir": true}
+					]}`
+				case "/missing":
+					return &http.Response{
+						Body: ioutil.NopCloser(strings.NewReader(
+							`{"success":false,"error":{"code":408}}`)),
+					}, nil
+				}
+				*calls = append(*calls, "list "+folder)
+			case fileCreateFolderAPI:
+				*calls = append(*calls, "mkdir "+folder+" "+r.FormValue("name"))
+			case fileUploadAPI:
+				_, h, err := r.FormFile("file")
+				ensure.Nil(t, err)
+				*calls = append(*calls, "upload "+r.FormValue("path")+" "+h.Filename)
+			case fileDeleteAPI:
+				*calls = append(*calls, "delete "+r.FormValue("path"))
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":true,"data":` + data + `}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+var mirrorSource = fstest.MapFS{
+	"same.txt":    {Data: []byte("abc"), ModTime: time.Unix(1500000000, 0)},
+	"changed.txt": {Data: []byte("abcd"), ModTime: time.Unix(1500000000, 0)},
+	"new.txt":     {Data: []byte("a")},
+	"sub/a.txt":   {Data: []byte("a")},
+}
+
+func TestClientMirror(t *testing.T) {
+	var calls []string
+	c := mirrorClient(t, &calls)
+	changes, err := c.Mirror(context.Background(), mirrorSource, "/dest/", MirrorOptions{
+		Delete: true,
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, changes, []MirrorChange{
+		{Op: MirrorUpload, Path: "/dest/changed.txt"},
+		{Op: MirrorUpload, Path: "/dest/new.txt"},
+		{Op: MirrorMkdir, Path: "/dest/sub"},
+		{Op: MirrorUpload, Path: "/dest/sub/a.txt"},
+		{Op: MirrorDelete, Path: "/dest/extra.txt"},
+		{Op: MirrorDelete, Path: "/dest/old"},
+	})
+	ensure.DeepEqual(t, calls, []string{
+		"list /dest",
+		"list /dest",
+		"upload /dest changed.txt",
+		"upload /dest new.txt",
+		`mkdir ["/dest"] ["sub"]`,
+		"upload /dest/sub a.txt",
+		`delete ["/dest/extra.txt"]`,
+		`delete ["/dest/old"]`,
+	})
+}
+
+func TestClientMirrorDryRun(t *testing.T) {
+	var calls []string
+	c := mirrorClient(t, &calls)
+	changes, err := c.Mirror(context.Background(), mirrorSource, "/missing", MirrorOptions{
+		DryRun: true,
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, changes, []MirrorChange{
+		{Op: MirrorMkdir, Path: "/missing"},
+		{Op: MirrorUpload, Path: "/missing/changed.txt"},
+		{Op: MirrorUpload, Path: "/missing/new.txt"},
+		{Op: MirrorUpload, Path: "/missing/same.txt"},
+		{Op: MirrorMkdir, Path: "/missing/sub"},
+		{Op: MirrorUpload, Path: "/missing/sub/a.txt"},
+	})
+	ensure.DeepEqual(t, len(calls), 0)
+}
@@ -0,0 +1,87 @@
+package syno
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// RequestFile is a file sent along with a Request as part of a
+// multipart/form-data body.
+type RequestFile struct {
+	// Name is the form field name, such as "file".
+	Name string
+
+	// Filename is the name of the file reported to the server.
+	Filename string
+
+	// Body provides the file contents. It is read while the request is sent,
+	// so it can only be sent once and requests with files are never retried.
+	Body io.Reader
+}
+
+// newMultipartRequest builds a multipart/form-data POST request with the
+// parameters as form fields followed by the files. The body is streamed, so
+// files are never buffered in memory.
+func newMultipartRequest(
+	ctx context.Context,
+	u *url.URL,
+	r *Request,
+	sid string,
+	token string,
+) (*http.Request, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), pr)
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", mw.FormDataContentType())
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, r, sid, token))
+	}()
+	return hreq, nil
+}
+
+func writeMultipart(mw *multipart.Writer, r *Request, sid, token string) error {
+	fields := [][2]string{
+		{"api", r.API},
+		{"version", r.Version},
+		{"method", r.Method},
+	}
+	if sid != "" {
+		fields = append(fields, [2]string{"_sid", sid})
+	}
+	if token != "" {
+		fields = append(fields, [2]string{"SynoToken", token})
+	}
+	keys := make([]string, 0, len(r.Params))
+	for k := range r.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range r.Params[k] {
+			fields = append(fields, [2]string{k, v})
+		}
+	}
+	for _, f := range fields {
+		if err := mw.WriteField(f[0], f[1]); err != nil {
+			return err
+		}
+	}
+	// DSM expects the files after all the other fields.
+	for _, f := range r.Files {
+		w, err := mw.CreateFormFile(f.Name, f.Filename)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, f.Body); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
@@ -0,0 +1,97 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func TestClientDoMultipart(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("sid"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/webapi/entry.cgi")
+			ensure.Nil(t, r.ParseMultipartForm(1<<20))
+			ensure.DeepEqual(t, url.Values(r.MultipartForm.Value), url.Values{
+				"api":     []string{"SYNO.FileStation.Upload"},
+				"version": []string{"2"},
+				"method":  []string{"upload"},
+				"_sid":    []string{"sid"},
+				"path":    []string{"/home"},
+			})
+			fh := r.MultipartForm.File["file"]
+			ensure.DeepEqual(t, len(fh), 1)
+			ensure.DeepEqual(t, fh[0].Filename, "a.txt")
+			f, err := fh[0].Open()
+			ensure.Nil(t, err)
+			b, err := ioutil.ReadAll(f)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, string(b), "hello")
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{
+		API:     "SYNO.FileStation.Upload",
+		Version: "2",
+		Method:  "upload",
+		Params:  url.Values{"path": []string{"/home"}},
+		Files: []RequestFile{{
+			Name:     "file",
+			Filename: "a.txt",
+			Body:     strings.NewReader("hello"),
+		}},
+	}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestClientDoMultipartBodyError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			_, err := ioutil.ReadAll(r.Body)
+			return nil, err
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{
+		Files: []RequestFile{{Name: "file", Body: errReader{err: givenErr}}},
+	}, nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientDoMultipartNotRetried(t *testing.T) {
+	var attempts int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(RetryPolicy{MinBackoff: 1, MaxBackoff: 1}),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			attempts++
+			ioutil.ReadAll(r.Body)
+			return &http.Response{
+				StatusCode: 503,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{
+		Files: []RequestFile{{Name: "file", Body: strings.NewReader("a")}},
+	}, nil)
+	ensure.DeepEqual(t, err, &HTTPError{StatusCode: 503, Body: []byte{}})
+	ensure.DeepEqual(t, attempts, 1)
+}
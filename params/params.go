@@ -0,0 +1,74 @@
+// Package params provides helpers to build the url.Values sent as API request
+// parameters. The helpers only set parameters for non-zero values, leaving
+// them out so the server applies its defaults.
+package params
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SetString sets the key to s if it is not empty.
+func SetString(v url.Values, key, s string) {
+	if s != "" {
+		v.Set(key, s)
+	}
+}
+
+// SetInt sets the key to n if it is not zero.
+func SetInt(v url.Values, key string, n int) {
+	SetInt64(v, key, int64(n))
+}
+
+// SetInt64 sets the key to n if it is not zero.
+func SetInt64(v url.Values, key string, n int64) {
+	if n != 0 {
+		v.Set(key, strconv.FormatInt(n, 10))
+	}
+}
+
+// SetBool sets the key to "true" if b is true.
+func SetBool(v url.Values, key string, b bool) {
+	if b {
+		v.Set(key, "true")
+	}
+}
+
+// SetYes sets the key to "yes" if b is true, as some APIs expect instead of
+// "true".
+func SetYes(v url.Values, key string, b bool) {
+	if b {
+		v.Set(key, "yes")
+	}
+}
+
+// SetCSV sets the key to the comma separated list of values if there are any.
+func SetCSV(v url.Values, key string, l []string) {
+	if len(l) > 0 {
+		v.Set(key, strings.Join(l, ","))
+	}
+}
+
+// SetJSONArray sets the key to the JSON array of values if there are any, as
+// used by APIs accepting multiple paths.
+func SetJSONArray(v url.Values, key string, l []string) {
+	if len(l) > 0 {
+		b, _ := json.Marshal(l)
+		v.Set(key, string(b))
+	}
+}
+
+// SetJSON sets the key to the JSON encoding of x if it is not nil.
+func SetJSON(v url.Values, key string, x interface{}) error {
+	if x == nil {
+		return nil
+	}
+	b, err := json.Marshal(x)
+	if err != nil {
+		return err
+	}
+	v.Set(key, string(b))
+	return nil
+}
@@ -0,0 +1,47 @@
+package params
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSetNonZero(t *testing.T) {
+	v := url.Values{}
+	SetString(v, "string", "a")
+	SetInt(v, "int", -1)
+	SetInt64(v, "int64", 1<<40)
+	SetBool(v, "bool", true)
+	SetYes(v, "yes", true)
+	SetCSV(v, "csv", []string{"a", "b"})
+	SetJSONArray(v, "array", []string{"/a", "/b"})
+	ensure.Nil(t, SetJSON(v, "json", map[string]int{"a": 1}))
+	ensure.DeepEqual(t, v, url.Values{
+		"string": []string{"a"},
+		"int":    []string{"-1"},
+		"int64":  []string{"1099511627776"},
+		"bool":   []string{"true"},
+		"yes":    []string{"yes"},
+		"csv":    []string{"a,b"},
+		"array":  []string{`["/a","/b"]`},
+		"json":   []string{`{"a":1}`},
+	})
+}
+
+func TestSetZero(t *testing.T) {
+	v := url.Values{}
+	SetString(v, "string", "")
+	SetInt(v, "int", 0)
+	SetInt64(v, "int64", 0)
+	SetBool(v, "bool", false)
+	SetYes(v, "yes", false)
+	SetCSV(v, "csv", nil)
+	SetJSONArray(v, "array", []string{})
+	ensure.Nil(t, SetJSON(v, "json", nil))
+	ensure.DeepEqual(t, v, url.Values{})
+}
+
+func TestSetJSONError(t *testing.T) {
+	ensure.NotNil(t, SetJSON(url.Values{}, "json", make(chan int)))
+}
@@ -0,0 +1,48 @@
+package syno
+
+import "io"
+
+// ProgressFunc is called as a transfer progresses with the number of bytes
+// transferred so far and the total, which is -1 if it is not known.
+type ProgressFunc func(bytesDone, bytesTotal int64)
+
+// progressReader calls a ProgressFunc as data is read through it.
+type progressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	f     ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.f(p.done, p.total)
+	}
+	return n, err
+}
+
+// ProgressReader returns a Reader that calls f as data is read from r, which
+// is expected to provide total bytes. This can be used to report the progress
+// of uploads as well as downloads.
+func ProgressReader(r io.Reader, total int64, f ProgressFunc) io.Reader {
+	if f == nil {
+		return r
+	}
+	return &progressReader{r: r, total: total, f: f}
+}
+
+type progressReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ProgressReadCloser is like ProgressReader, but preserves the Close method of
+// the underlying ReadCloser, such as a response body.
+func ProgressReadCloser(r io.ReadCloser, total int64, f ProgressFunc) io.ReadCloser {
+	if f == nil {
+		return r
+	}
+	return progressReadCloser{Reader: ProgressReader(r, total, f), Closer: r}
+}
@@ -0,0 +1,57 @@
+package syno
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+type progressCall struct {
+	Done, Total int64
+}
+
+func TestProgressReader(t *testing.T) {
+	var calls []progressCall
+	r := ProgressReader(
+		io.MultiReader(strings.NewReader("abc"), strings.NewReader("de")),
+		5,
+		func(done, total int64) { calls = append(calls, progressCall{done, total}) },
+	)
+	b, err := ioutil.ReadAll(r)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), "abcde")
+	ensure.DeepEqual(t, calls, []progressCall{{3, 5}, {5, 5}})
+}
+
+func TestProgressReaderNil(t *testing.T) {
+	r := strings.NewReader("")
+	ensure.True(t, ProgressReader(r, -1, nil) == io.Reader(r))
+	rc := ioutil.NopCloser(r)
+	ensure.True(t, ProgressReadCloser(rc, -1, nil) == rc)
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestProgressReadCloser(t *testing.T) {
+	var last progressCall
+	c := &closeRecorder{Reader: strings.NewReader("abc")}
+	rc := ProgressReadCloser(c, -1, func(done, total int64) {
+		last = progressCall{done, total}
+	})
+	_, err := ioutil.ReadAll(rc)
+	ensure.Nil(t, err)
+	ensure.Nil(t, rc.Close())
+	ensure.True(t, c.closed)
+	ensure.DeepEqual(t, last, progressCall{3, -1})
+}
@@ -0,0 +1,222 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// transportError marks errors returned by the transport, so they can be told
+// apart from errors decoding the response.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+
+func (e *transportError) Unwrap() error { return e.err }
+
+var (
+	retryableMu    sync.RWMutex
+	retryableCodes = map[string]map[Error]bool{}
+)
+
+// RegisterRetryableErrors marks the given codes as transient when returned by
+// the API, such as "SYNO.FileStation", so IsRetryable reports true for them.
+// As with RegisterAPIErrorStrings the scope applies to the APIs below it, and
+// an empty API marks the codes as transient for every API.
+func RegisterRetryableErrors(api string, codes ...Error) {
+	retryableMu.Lock()
+	defer retryableMu.Unlock()
+	scoped := retryableCodes[api]
+	if scoped == nil {
+		scoped = make(map[Error]bool, len(codes))
+		retryableCodes[api] = scoped
+	}
+	for _, e := range codes {
+		scoped[e] = true
+	}
+}
+
+func isRetryableCode(api string, e Error) bool {
+	retryableMu.RLock()
+	defer retryableMu.RUnlock()
+	for api != "" {
+		if retryableCodes[api][e] {
+			return true
+		}
+		i := strings.LastIndexByte(api, '.')
+		if i < 0 {
+			break
+		}
+		api = api[:i]
+	}
+	return retryableCodes[""][e]
+}
+
+// IsRetryable reports if the error returned by Do is transient, meaning the
+// same request may succeed if made again. Network timeouts, connections reset
+// or closed before the response was read, the HTTP statuses 429, 500, 502, 503
+// and 504, and API error codes registered with RegisterRetryableErrors are
+// transient. Other transport errors, such as TLS certificate failures or
+// malformed compressed responses, and context cancellation and deadlines
+// never are.
+func IsRetryable(err error) bool {
+	if err == nil ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var he *HTTPError
+	if errors.As(err, &he) {
+		switch he.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return isRetryableCode(ae.API, ae.Code)
+	}
+	var code Error
+	if errors.As(err, &code) {
+		return isRetryableCode("", code)
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// readMethods are the prefixes of the API methods that only read state.
+var readMethods = []string{"get", "list", "query", "status", "check", "download"}
+
+// isReadMethod reports if the API method only reads state, such as "list" or
+// "GetInfo".
+func isReadMethod(method string) bool {
+	method = strings.ToLower(method)
+	for _, prefix := range readMethods {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryPolicy configures how ClientRetry retries failed requests. Errors that
+// IsRetryable reports as transient are always retried, while other API errors
+// are only retried if their code is listed in Codes.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. If
+	// zero, 3 is used.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry, which is doubled for
+	// every subsequent one. If zero, 100ms is used.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. If zero, 10s is used.
+	MaxBackoff time.Duration
+
+	// Codes are the API error codes to retry.
+	Codes []Error
+
+	// Idempotent reports if the request can safely be made more than once.
+	// Other requests are never retried, as the failed attempt may have
+	// reached the server, and making a request such as DownloadTaskCreate
+	// again would create a duplicate. If nil, requests are idempotent if they
+	// are Cacheable or their Method only reads, such as "list" or "GetInfo".
+	Idempotent func(r *Request) bool
+}
+
+func (p *RetryPolicy) idempotent(r *Request) bool {
+	if p.Idempotent != nil {
+		return p.Idempotent(r)
+	}
+	return r.Cacheable || isReadMethod(r.Method)
+}
+
+func (p *RetryPolicy) retryable(r *Request, err error) bool {
+	if IsRetryable(wrapAPIError(r, err)) {
+		return true
+	}
+	var code Error
+	if errors.As(err, &code) {
+		for _, c := range p.Codes {
+			if c == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// backoff returns the jittered delay before the given retry, starting at 0.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 10 * time.Second
+	}
+	d := max
+	if retry < 32 && min<<uint(retry) > 0 && min<<uint(retry) < max {
+		d = min << uint(retry)
+	}
+	// Full jitter over the upper half keeps a meaningful minimum delay while
+	// still spreading out clients that failed together.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// do calls f until it succeeds, fails with an error that is not retryable,
+// the attempts are exhausted or the context is done. Requests that are not
+// idempotent are only attempted once.
+func (p *RetryPolicy) do(ctx context.Context, r *Request, f func() error) error {
+	attempts := p.MaxAttempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	if !p.idempotent(r) {
+		attempts = 1
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			t := time.NewTimer(p.backoff(i - 1))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+		if err = f(); err == nil || !p.retryable(r, err) {
+			return err
+		}
+	}
+	return err
+}
+
+// ClientRetry configures the Client to retry transient failures of idempotent
+// requests according to the given policy, with jittered exponential backoff
+// between attempts.
+func ClientRetry(p RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.retry = &p
+		return nil
+	}
+}
@@ -0,0 +1,278 @@
+package syno
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Microsecond,
+	MaxBackoff:  time.Millisecond,
+}
+
+// resetErr is a transient transport failure.
+var resetErr = &net.OpError{Op: "read", Err: syscall.ECONNRESET}
+
+func TestRetryTransportError(t *testing.T) {
+	var calls int
+	givenErr := resetErr
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(testRetryPolicy),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls < 3 {
+				return nil, givenErr
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ensure.Nil(t, c.Do(context.Background(), &Request{Method: "list"}, nil))
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestRetryExhausted(t *testing.T) {
+	var calls int
+	givenErr := resetErr
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(testRetryPolicy),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return nil, givenErr
+		})),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Do(context.Background(), &Request{Method: "list"}, nil), givenErr)
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestRetryServerError(t *testing.T) {
+	var calls int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(testRetryPolicy),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Body:       ioutil.NopCloser(jsonpipe.Encode("")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{Method: "list"}, nil)
+	ensure.DeepEqual(t, err.(*HTTPError).StatusCode, http.StatusBadGateway)
+	ensure.DeepEqual(t, err.Error(), `syno: HTTP status 502: ""`)
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestRetryCodes(t *testing.T) {
+	var calls int
+	p := testRetryPolicy
+	p.Codes = []Error{ErrorUnknown}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(p),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			code := ErrorUnknown
+			if calls > 1 {
+				code = ErrorInvalidParameter
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"error": map[string]interface{}{"code": code},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{Method: "list"}, nil)
+	ensure.True(t, errors.Is(err, ErrorInvalidParameter))
+	ensure.DeepEqual(t, calls, 2)
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	var calls int
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(RetryPolicy{MinBackoff: time.Hour}),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			cancel()
+			return nil, resetErr
+		})),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Do(ctx, &Request{Method: "list"}, nil), context.Canceled)
+	ensure.DeepEqual(t, calls, 1)
+}
+
+func TestRetryNotRetryableContextError(t *testing.T) {
+	p := RetryPolicy{}
+	r := &Request{}
+	ensure.False(t, p.retryable(r, &transportError{err: context.Canceled}))
+	ensure.False(t, p.retryable(r, errors.New("")))
+	ensure.True(t, p.retryable(r, &transportError{err: resetErr}))
+}
+
+func TestRetryScopedCode(t *testing.T) {
+	p := RetryPolicy{}
+	ensure.True(t, p.retryable(&Request{API: "SYNO.FileStation.List"}, ErrorFileBusy))
+	ensure.False(t, p.retryable(&Request{API: "SYNO.DownloadStation.Task"}, ErrorFileBusy))
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Err   error
+		Retry bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New(""), false},
+		{"transport", &transportError{err: errors.New("")}, false},
+		{"reset", &transportError{err: resetErr}, true},
+		{"broken pipe", &net.OpError{Op: "write", Err: syscall.EPIPE}, true},
+		{"tls", &transportError{err: x509.UnknownAuthorityError{}}, false},
+		{"gzip", &transportError{err: gzip.ErrHeader}, false},
+		{"canceled", context.Canceled, false},
+		{"deadline", &transportError{err: context.DeadlineExceeded}, false},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"net", &net.OpError{Op: "dial", Err: errors.New("")}, false},
+		{"timeout", &url.Error{Op: "Get", Err: timeoutErr{}}, true},
+		{"502", &HTTPError{StatusCode: http.StatusBadGateway}, true},
+		{"503", &HTTPError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"429", &HTTPError{StatusCode: http.StatusTooManyRequests}, true},
+		{"404", &HTTPError{StatusCode: http.StatusNotFound}, false},
+		{"501", &HTTPError{StatusCode: http.StatusNotImplemented}, false},
+		{"code", ErrorInvalidParameter, false},
+		{"session", ErrorSessionTimeout, false},
+		{"busy", &APIError{API: "SYNO.FileStation.CopyMove", Code: ErrorFileSystemBusy}, true},
+		{"busy other api", &APIError{API: "SYNO.API.Auth", Code: ErrorFileSystemBusy}, false},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, IsRetryable(c.Err), c.Retry, c.Name)
+	}
+}
+
+// restoreRetryableCodes undoes the codes registered by the test once it is
+// done.
+func restoreRetryableCodes(t *testing.T) {
+	retryableMu.Lock()
+	saved := make(map[string]map[Error]bool, len(retryableCodes))
+	for api, codes := range retryableCodes {
+		saved[api] = make(map[Error]bool, len(codes))
+		for code := range codes {
+			saved[api][code] = true
+		}
+	}
+	retryableMu.Unlock()
+	t.Cleanup(func() {
+		retryableMu.Lock()
+		retryableCodes = saved
+		retryableMu.Unlock()
+	})
+}
+
+func TestRegisterRetryableErrors(t *testing.T) {
+	restoreRetryableCodes(t)
+	const code = Error(9901)
+	err := &APIError{API: "SYNO.Test.Retry", Code: code}
+	ensure.False(t, IsRetryable(err))
+	RegisterRetryableErrors("SYNO.Test", code)
+	ensure.True(t, IsRetryable(err))
+	ensure.False(t, IsRetryable(code))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 4 * time.Second}
+	for retry, max := range []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		4 * time.Second,
+		4 * time.Second,
+	} {
+		d := p.backoff(retry)
+		ensure.True(t, d >= max/2 && d <= max, retry, d)
+	}
+	ensure.True(t, p.backoff(100) <= 4*time.Second)
+}
+
+func TestRetryBackoffDefaults(t *testing.T) {
+	p := RetryPolicy{}
+	d := p.backoff(0)
+	ensure.True(t, d >= 50*time.Millisecond && d <= 100*time.Millisecond, d)
+	d = p.backoff(20)
+	ensure.True(t, d >= 5*time.Second && d <= 10*time.Second, d)
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestRetryNotIdempotent(t *testing.T) {
+	var calls int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(testRetryPolicy),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return nil, resetErr
+		})),
+	)
+	ensure.Nil(t, err)
+	r, err := DownloadTaskCreate{URI: "http://a"}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Do(context.Background(), r, nil), resetErr)
+	ensure.DeepEqual(t, calls, 1)
+}
+
+func TestRetryIdempotentOptIn(t *testing.T) {
+	var calls int
+	p := testRetryPolicy
+	p.Idempotent = func(r *Request) bool { return r.Method == "create" }
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientRetry(p),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return nil, resetErr
+		})),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Do(context.Background(), &Request{Method: "create"}, nil), resetErr)
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestIsReadMethod(t *testing.T) {
+	ensure.True(t, isReadMethod("list"))
+	ensure.True(t, isReadMethod("GetInfo"))
+	ensure.True(t, isReadMethod("getinfo"))
+	ensure.False(t, isReadMethod("create"))
+	ensure.False(t, isReadMethod("SaveBookmark"))
+	ensure.False(t, isReadMethod(""))
+}
@@ -0,0 +1,99 @@
+package syno
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// StoredSession is a session persisted by a SessionStore.
+type StoredSession struct {
+	SID       string `json:"sid"`
+	SynoToken string `json:"synotoken,omitempty"`
+}
+
+// SessionStore persists the default session of a Client, so it can be reused
+// across processes instead of logging in every time.
+type SessionStore interface {
+	// Load returns the stored session, or nil if there is none.
+	Load() (*StoredSession, error)
+
+	// Save stores the session. A session with an empty SID indicates the
+	// stored session, if any, should be removed.
+	Save(*StoredSession) error
+}
+
+// FileSessionStore is a SessionStore which stores the session as JSON in the
+// file at the given path. The file is only readable by the current user.
+type FileSessionStore string
+
+// Load returns the stored session, or nil if the file does not exist.
+func (f FileSessionStore) Load() (*StoredSession, error) {
+	b, err := ioutil.ReadFile(string(f))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s StoredSession
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the session to the file, or removes the file if the SID is
+// empty. The file is replaced atomically.
+func (f FileSessionStore) Save(s *StoredSession) error {
+	path := string(f)
+	if s.SID == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ClientSessionStore configures the SessionStore used to persist the default
+// session. A stored session is loaded immediately, and logins made by the
+// Client are saved to it. It should be specified before ClientLogin,
+// ClientEncryptedLogin or ClientSSOLogin, which then only log in if no session
+// was loaded. Combine it with
+// ClientAutoRelogin to recover from a stored session having expired.
+func ClientSessionStore(store SessionStore) ClientOption {
+	return func(c *Client) error {
+		c.sessionStore = store
+		s, err := store.Load()
+		if err != nil {
+			return err
+		}
+		if s == nil || s.SID == "" {
+			return nil
+		}
+		c.restored = true
+		c.storeLoginLocked("", true, &AuthLoginResponse{
+			SID:       s.SID,
+			SynoToken: s.SynoToken,
+		})
+		return nil
+	}
+}
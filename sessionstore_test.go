@@ -0,0 +1,152 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func tempSessionStore(t *testing.T) FileSessionStore {
+	return FileSessionStore(filepath.Join(t.TempDir(), "session.json"))
+}
+
+func TestFileSessionStoreMissing(t *testing.T) {
+	s, err := tempSessionStore(t).Load()
+	ensure.Nil(t, err)
+	ensure.True(t, s == nil)
+}
+
+func TestFileSessionStoreRoundTrip(t *testing.T) {
+	store := tempSessionStore(t)
+	given := &StoredSession{SID: "sid", SynoToken: "token"}
+	ensure.Nil(t, store.Save(given))
+	fi, err := os.Stat(string(store))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, fi.Mode().Perm(), os.FileMode(0600))
+	s, err := store.Load()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s, given)
+
+	ensure.Nil(t, store.Save(&StoredSession{}))
+	s, err = store.Load()
+	ensure.Nil(t, err)
+	ensure.True(t, s == nil)
+	ensure.Nil(t, store.Save(&StoredSession{}))
+}
+
+func TestFileSessionStoreInvalid(t *testing.T) {
+	store := tempSessionStore(t)
+	ensure.Nil(t, ioutil.WriteFile(string(store), []byte("."), 0600))
+	_, err := store.Load()
+	ensure.NotNil(t, err)
+}
+
+func TestFileSessionStoreSaveError(t *testing.T) {
+	store := FileSessionStore(filepath.Join(t.TempDir(), "missing", "session"))
+	ensure.NotNil(t, store.Save(&StoredSession{SID: "sid"}))
+}
+
+func TestClientSessionStoreRestore(t *testing.T) {
+	store := tempSessionStore(t)
+	ensure.Nil(t, store.Save(&StoredSession{SID: "stored", SynoToken: "token"}))
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			t.Fatal("unexpected request")
+			return nil, nil
+		})),
+		ClientSessionStore(store),
+		ClientLogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.SID(), "stored")
+	ensure.DeepEqual(t, c.synoToken("stored"), "token")
+}
+
+func TestClientSessionStoreRestoreLogins(t *testing.T) {
+	logins := map[string]ClientOption{
+		"encrypted": ClientEncryptedLogin(AuthLogin{Account: "a", Password: "b"}),
+		"sso":       ClientSSOLogin(AuthSSOLogin{AccessToken: "token"}),
+	}
+	for name, login := range logins {
+		store := tempSessionStore(t)
+		ensure.Nil(t, store.Save(&StoredSession{SID: "stored"}))
+		c, err := NewClient(
+			ClientRawURL("http://foo.com/"),
+			ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+				t.Fatalf("%s: unexpected request", name)
+				return nil, nil
+			})),
+			ClientSessionStore(store),
+			login,
+		)
+		ensure.Nil(t, err, name)
+		ensure.DeepEqual(t, c.SID(), "stored", name)
+	}
+}
+
+func TestClientSessionStoreSave(t *testing.T) {
+	store := tempSessionStore(t)
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    map[string]string{"sid": "sid", "synotoken": "token"},
+				})),
+			}, nil
+		})),
+		ClientSessionStore(store),
+		ClientLogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	s, err := store.Load()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s, &StoredSession{SID: "sid", SynoToken: "token"})
+
+	ensure.Nil(t, c.Close(context.Background()))
+	s, err = store.Load()
+	ensure.Nil(t, err)
+	ensure.True(t, s == nil)
+}
+
+type errSessionStore struct{ err error }
+
+func (s errSessionStore) Load() (*StoredSession, error) { return nil, s.err }
+func (s errSessionStore) Save(*StoredSession) error     { return s.err }
+
+func TestClientSessionStoreLoadError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSessionStore(errSessionStore{givenErr}),
+	)
+	ensure.True(t, c == nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientSessionStoreCloseError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	c.SetSID("sid")
+	c.sessionStore = errSessionStore{givenErr}
+	ensure.DeepEqual(t, c.Close(context.Background()), givenErr)
+}
@@ -0,0 +1,222 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+var testSSOConfig = SSOConfig{
+	AppID:       "app",
+	AppSecret:   "secret",
+	RedirectURI: "http://app.com/cb",
+}
+
+func TestSSOAuthCodeURL(t *testing.T) {
+	c, err := NewClient(ClientRawURL("https://nas.com/"))
+	ensure.Nil(t, err)
+	u, err := url.Parse(c.SSOAuthCodeURL(testSSOConfig, "state"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, u.Host, "nas.com")
+	ensure.DeepEqual(t, u.Path, ssoAuthorizePath)
+	ensure.DeepEqual(t, u.Query(), url.Values{
+		"response_type": []string{"code"},
+		"client_id":     []string{"app"},
+		"redirect_uri":  []string{"http://app.com/cb"},
+		"scope":         []string{"openid"},
+		"state":         []string{"state"},
+	})
+}
+
+func TestSSOAuthCodeURLScopes(t *testing.T) {
+	c, err := NewClient(ClientRawURL("https://nas.com/"))
+	ensure.Nil(t, err)
+	cfg := testSSOConfig
+	cfg.Scopes = []string{"openid", "email"}
+	u, err := url.Parse(c.SSOAuthCodeURL(cfg, ""))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, u.Query()["scope"], []string{"openid email"})
+	ensure.DeepEqual(t, u.Query()["state"], []string(nil))
+}
+
+func TestSSOExchange(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, ssoTokenPath)
+			ensure.Nil(t, r.ParseForm())
+			ensure.DeepEqual(t, r.PostForm, url.Values{
+				"grant_type":    []string{"authorization_code"},
+				"code":          []string{"code"},
+				"redirect_uri":  []string{"http://app.com/cb"},
+				"client_id":     []string{"app"},
+				"client_secret": []string{"secret"},
+			})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"access_token": "token",
+					"token_type":   "bearer",
+					"expires_in":   3600,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	token, err := c.SSOExchange(context.Background(), testSSOConfig, "code")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, token, &SSOToken{
+		AccessToken: "token",
+		TokenType:   "bearer",
+		ExpiresIn:   3600,
+	})
+}
+
+func TestSSOExchangeError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"error":             "invalid_grant",
+					"error_description": "code expired",
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	_, err = c.SSOExchange(context.Background(), testSSOConfig, "code")
+	ensure.DeepEqual(t, err, &SSOError{
+		Code:        "invalid_grant",
+		Description: "code expired",
+	})
+	ensure.DeepEqual(t, err.Error(), "syno: sso invalid_grant: code expired")
+	ensure.DeepEqual(t, (&SSOError{Code: "x"}).Error(), "syno: sso x")
+}
+
+func TestSSOExchangeHTTPError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Body:       ioutil.NopCloser(strings.NewReader("<html>")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	_, err = c.SSOExchange(context.Background(), testSSOConfig, "code")
+	ensure.DeepEqual(t, err, &HTTPError{
+		StatusCode: http.StatusBadGateway,
+		Body:       []byte("<html>"),
+	})
+}
+
+func TestSSOExchangeStatusSSOError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"error":"invalid_grant","error_description":"code expired"}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	_, err = c.SSOExchange(context.Background(), testSSOConfig, "code")
+	ensure.DeepEqual(t, err, &SSOError{
+		Code:        "invalid_grant",
+		Description: "code expired",
+	})
+}
+
+func TestSSOExchangeDecodeError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("<html>")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	_, err = c.SSOExchange(context.Background(), testSSOConfig, "code")
+	var de *DecodeError
+	ensure.True(t, errors.As(err, &de), err)
+	ensure.DeepEqual(t, de.URL, "https://nas.com"+ssoTokenPath)
+	ensure.DeepEqual(t, de.Body, []byte("<html>"))
+}
+
+func TestSSOExchangeTransportError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, givenErr
+		})),
+	)
+	ensure.Nil(t, err)
+	_, err = c.SSOExchange(context.Background(), testSSOConfig, "code")
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestAuthSSOLoginMarshal(t *testing.T) {
+	r, err := AuthSSOLogin{AccessToken: "token"}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    authLoginPath,
+		API:     authLoginAPI,
+		Version: authLoginVersion,
+		Method:  "login",
+		Params:  url.Values{"access_token": []string{"token"}},
+	})
+}
+
+func TestClientSSOLogin(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			ensure.Subset(t, v, url.Values{
+				"access_token": []string{"token"},
+				"format":       []string{"sid"},
+			})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    map[string]string{"sid": "sid"},
+				})),
+			}, nil
+		})),
+		ClientSSOLogin(AuthSSOLogin{
+			AccessToken: "token",
+			Session:     SessionFileStation,
+		}),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.sid, "sid")
+	ensure.DeepEqual(t, c.sessions[SessionFileStation], "sid")
+}
+
+func TestClientSSOLoginError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("https://nas.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, givenErr
+		})),
+		ClientSSOLogin(AuthSSOLogin{AccessToken: "token"}),
+	)
+	ensure.True(t, c == nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
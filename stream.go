@@ -0,0 +1,117 @@
+package syno
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+)
+
+// Stream is the body of a response from an API that returns binary data, such
+// as file downloads, thumbnails and snapshots. It must be closed once read.
+type Stream struct {
+	io.ReadCloser
+
+	// ContentType is the media type of the body as reported by the server.
+	ContentType string
+
+	// ContentLength is the length of the body, or -1 if it is not known.
+	ContentLength int64
+
+	// Filename is the name of the file as reported by the server in the
+	// Content-Disposition header, if any.
+	Filename string
+
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the resources associated with the
+// request.
+func (s *Stream) Close() error {
+	err := s.ReadCloser.Close()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return err
+}
+
+// DoStream performs an API request like Do, but returns the response body as
+// is instead of decoding it. Binary APIs report failures as a regular JSON
+// response, which is decoded and returned as an error. Any Timeout covers
+// reading the body as well, and lasts until the Stream is closed.
+func (c *Client) DoStream(ctx context.Context, r *Request) (*Stream, error) {
+	var s Stream
+	if err := c.Do(ctx, r, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func isJSON(contentType string) bool {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	return mt == "application/json" || mt == "text/json"
+}
+
+// dispositionFilename returns the filename parameter of a Content-Disposition
+// header, or an empty string if there is none.
+func dispositionFilename(cd string) string {
+	if cd == "" {
+		return ""
+	}
+	_, p, err := mime.ParseMediaType(cd)
+	if err != nil {
+		return ""
+	}
+	return p["filename"]
+}
+
+// stream fills the Stream from the response and takes ownership of its body,
+// unless it turns out to be an error.
+func (c *Client) stream(hreq *http.Request, hres *http.Response, s *Stream) error {
+	if c.debug != nil {
+		fmt.Fprintf(
+			c.debug,
+			"syno: response %d: %s stream of %d bytes\n",
+			hres.StatusCode,
+			hres.Header.Get("Content-Type"),
+			hres.ContentLength,
+		)
+	}
+	if err := newHTTPError(hres); err != nil {
+		hres.Body.Close()
+		return err
+	}
+	contentType := hres.Header.Get("Content-Type")
+	if !isJSON(contentType) {
+		*s = Stream{
+			ReadCloser:    hres.Body,
+			ContentType:   contentType,
+			ContentLength: hres.ContentLength,
+			Filename:      dispositionFilename(hres.Header.Get("Content-Disposition")),
+		}
+		return nil
+	}
+
+	defer hres.Body.Close()
+	body, err := ioutil.ReadAll(hres.Body)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	var res Response
+	if err := json.Unmarshal(body, &res); err != nil {
+		return newDecodeError(hreq, body, err)
+	}
+	if !res.Success {
+		return res.Error.err()
+	}
+	*s = Stream{
+		ReadCloser:    ioutil.NopCloser(bytes.NewReader(body)),
+		ContentType:   contentType,
+		ContentLength: int64(len(body)),
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package syno
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func streamClient(t *testing.T, res func() *http.Response, options ...ClientOption) *Client {
+	options = append([]ClientOption{
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return res(), nil
+		})),
+	}, options...)
+	c, err := NewClient(options...)
+	ensure.Nil(t, err)
+	return c
+}
+
+func TestClientDoStream(t *testing.T) {
+	var debug bytes.Buffer
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode:    200,
+			Header:        http.Header{"Content-Type": []string{"image/jpeg"}},
+			ContentLength: 4,
+			Body:          ioutil.NopCloser(strings.NewReader("\xff\xd8\xff\xe0")),
+		}
+	}, ClientDebug(&debug), ClientTimeout(time.Minute))
+	s, err := c.DoStream(context.Background(), &Request{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.ContentType, "image/jpeg")
+	ensure.DeepEqual(t, s.ContentLength, int64(4))
+	b, err := ioutil.ReadAll(s)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), "\xff\xd8\xff\xe0")
+	ensure.True(t, s.cancel != nil)
+	ensure.Nil(t, s.Close())
+	ensure.StringContains(t, debug.String(), "image/jpeg stream of 4 bytes")
+}
+
+func TestClientDoStreamError(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Content-Type": []string{"application/json; charset=utf-8"},
+			},
+			Body: ioutil.NopCloser(strings.NewReader(
+				`{"success":false,"error":{"code":408}}`)),
+		}
+	})
+	s, err := c.DoStream(context.Background(), &Request{})
+	ensure.True(t, s == nil)
+	ensure.True(t, errors.Is(err, Error(408)))
+}
+
+func TestClientDoStreamJSON(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"success":true}`)),
+		}
+	})
+	s, err := c.DoStream(context.Background(), &Request{})
+	ensure.Nil(t, err)
+	b, err := ioutil.ReadAll(s)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), `{"success":true}`)
+	ensure.DeepEqual(t, s.ContentLength, int64(len(b)))
+	ensure.Nil(t, s.Close())
+}
+
+func TestClientDoStreamInvalidJSON(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{`)),
+		}
+	})
+	_, err := c.DoStream(context.Background(), &Request{})
+	ensure.NotNil(t, err)
+}
+
+func TestClientDoStreamStatusError(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 502,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	}, ClientTimeout(time.Minute))
+	_, err := c.DoStream(context.Background(), &Request{})
+	ensure.DeepEqual(t, err, &HTTPError{StatusCode: 502, Body: []byte{}})
+}
@@ -0,0 +1,118 @@
+package syno
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// SurveillanceCameraConfig is the connection and stream settings of an IP
+// camera, such as an ONVIF one, as used to add it to Surveillance Station.
+// The Camera wizard APIs are not part of the published API guide, and their
+// parameters are those sent by the add camera wizard of the web interface.
+type SurveillanceCameraConfig struct {
+	Name string `syno:"name,omitempty"`
+
+	// Host and Port are where the camera is reached. Port is 80 if zero.
+	Host string `syno:"ip"`
+	Port int    `syno:"port,omitempty"`
+
+	// Vendor and Model are as listed by Surveillance Station, such as
+	// "ONVIF" and "Generic_ONVIF" for cameras supporting ONVIF.
+	Vendor string `syno:"vendor"`
+	Model  string `syno:"model"`
+
+	User     string `syno:"userName,omitempty"`
+	Password string `syno:"password,omitempty"`
+
+	// Channel is the channel of a video server, and is ignored by cameras.
+	Channel int `syno:"channel,omitempty"`
+
+	// Streams are the stream settings of the profiles, which are left to the
+	// camera if empty.
+	Streams []SurveillanceStreamSettings `syno:"stream,omitempty,json"`
+}
+
+// validate checks that the fields required to reach the camera are given.
+func (s SurveillanceCameraConfig) validate() error {
+	if err := requireField("Host", s.Host); err != nil {
+		return err
+	}
+	if err := requireField("Vendor", s.Vendor); err != nil {
+		return err
+	}
+	return requireField("Model", s.Model)
+}
+
+// SurveillanceStreamSettings are the video settings of a stream profile of a
+// camera.
+type SurveillanceStreamSettings struct {
+	Profile    SurveillanceProfile `json:"profileType"`
+	Codec      string              `json:"videoCodec,omitempty"`
+	Resolution string              `json:"resolution,omitempty"`
+	FPS        int                 `json:"fps,omitempty"`
+	Quality    string              `json:"quality,omitempty"`
+
+	// Bitrate is in kbps, and is only used by cameras with a constant
+	// bitrate.
+	Bitrate int `json:"bitrate,omitempty"`
+}
+
+// SurveillanceCameraTestConnection checks that Surveillance Station can
+// connect to a camera with the settings, before saving it. It fails with an
+// error such as ErrorSurveillanceTestConnection if it cannot, and does not
+// have a response otherwise.
+type SurveillanceCameraTestConnection struct {
+	Camera SurveillanceCameraConfig
+}
+
+// Validate checks that the fields required to reach the camera are given.
+func (s SurveillanceCameraTestConnection) Validate() error {
+	return s.Camera.validate()
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraTestConnection) MarshalRequest() (*Request, error) {
+	r, err := surveillanceRequest(surveillanceCameraAPI,
+		surveillanceCameraVersion, "TestConnection", s.Camera)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+// SurveillanceCameraSave adds a camera, or changes the settings of an
+// existing one if ID is set. The response is SurveillanceCameraSaveResponse.
+type SurveillanceCameraSave struct {
+	ID     int
+	Camera SurveillanceCameraConfig
+}
+
+// Validate checks that the name and the fields required to reach the camera
+// are given.
+func (s SurveillanceCameraSave) Validate() error {
+	if err := requireField("Name", s.Camera.Name); err != nil {
+		return err
+	}
+	return s.Camera.validate()
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraSave) MarshalRequest() (*Request, error) {
+	r, err := surveillanceRequest(surveillanceCameraAPI,
+		surveillanceCameraVersion, "Save", s.Camera)
+	if err != nil {
+		return nil, err
+	}
+	if s.ID != 0 {
+		r.Params.Set("id", strconv.Itoa(s.ID))
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+// SurveillanceCameraSaveResponse is the response for SurveillanceCameraSave,
+// with the ID of the added or changed camera.
+type SurveillanceCameraSaveResponse struct {
+	ID int `json:"id"`
+}
@@ -0,0 +1,64 @@
+package syno
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+var testCameraConfig = SurveillanceCameraConfig{
+	Name:     "Driveway",
+	Host:     "10.0.0.5",
+	Vendor:   "ONVIF",
+	Model:    "Generic_ONVIF",
+	User:     "admin",
+	Password: "secret",
+	Streams: []SurveillanceStreamSettings{{
+		Profile:    SurveillanceProfileHigh,
+		Codec:      "H.264",
+		Resolution: "1920x1080",
+		FPS:        15,
+	}},
+}
+
+func TestSurveillanceCameraSaveMarshal(t *testing.T) {
+	r, err := SurveillanceCameraSave{ID: 3, Camera: testCameraConfig}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       surveillancePath,
+		API:        surveillanceCameraAPI,
+		Version:    surveillanceCameraVersion,
+		Method:     "Save",
+		HTTPMethod: http.MethodPost,
+		Params: url.Values{
+			"id":       []string{"3"},
+			"name":     []string{"Driveway"},
+			"ip":       []string{"10.0.0.5"},
+			"vendor":   []string{"ONVIF"},
+			"model":    []string{"Generic_ONVIF"},
+			"userName": []string{"admin"},
+			"password": []string{"secret"},
+			"stream": []string{
+				`[{"profileType":0,"videoCodec":"H.264","resolution":"1920x1080","fps":15}]`,
+			},
+		},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillanceCameraTestConnectionMarshal(t *testing.T) {
+	r, err := SurveillanceCameraTestConnection{
+		Camera: SurveillanceCameraConfig{Host: "10.0.0.5", Port: 8080, Vendor: "a", Model: "b"},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "TestConnection")
+	ensure.DeepEqual(t, r.HTTPMethod, http.MethodPost)
+	ensure.DeepEqual(t, r.Params, url.Values{
+		"ip":     []string{"10.0.0.5"},
+		"port":   []string{"8080"},
+		"vendor": []string{"a"},
+		"model":  []string{"b"},
+	})
+}
@@ -0,0 +1,109 @@
+package syno
+
+import "net/http"
+
+const (
+	surveillanceEventAPI     = "SYNO.SurveillanceStation.Camera.Event"
+	surveillanceEventVersion = "1"
+)
+
+// SurveillanceMotionGet gets the motion detection settings of a camera. The
+// response is SurveillanceMotionSettings.
+type SurveillanceMotionGet struct {
+	CameraID int `syno:"camId"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillanceMotionGet) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceMotionGet) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(
+		surveillanceEventAPI, surveillanceEventVersion, "MotionEnum", s)
+}
+
+// SurveillanceMotionSettings is the response for SurveillanceMotionGet.
+type SurveillanceMotionSettings struct {
+	Param SurveillanceMotionParam `json:"MDParam"`
+}
+
+// SurveillanceMotionParam are the motion detection settings of a camera. The
+// ranges give the values supported by the camera along with the current one.
+type SurveillanceMotionParam struct {
+	Source SurveillanceMotionSource `json:"source"`
+
+	// Keep keeps the settings of the camera itself instead of overwriting
+	// them, when motion is detected by the camera.
+	Keep bool `json:"keep"`
+
+	Sensitivity SurveillanceRange `json:"sensitivity"`
+	Threshold   SurveillanceRange `json:"threshold"`
+	ObjectSize  SurveillanceRange `json:"object_size"`
+	Percentage  SurveillanceRange `json:"percentage"`
+	History     SurveillanceRange `json:"history"`
+
+	// Region is the detection area, in the encoding used by the server. It is
+	// best copied from one camera to another rather than built by hand.
+	Region string `json:"region"`
+}
+
+// SurveillanceRange is a setting with the range of values it supports.
+type SurveillanceRange struct {
+	Value int `json:"value"`
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+}
+
+// SurveillanceMotionSet changes the motion detection settings of a camera.
+// The settings left as nil are not changed. It does not have a response.
+type SurveillanceMotionSet struct {
+	CameraID    int                       `syno:"camId"`
+	Source      *SurveillanceMotionSource `syno:"source"`
+	Keep        *bool                     `syno:"keep"`
+	Sensitivity *int                      `syno:"sensitivity"`
+	Threshold   *int                      `syno:"threshold"`
+	ObjectSize  *int                      `syno:"objectSize"`
+	Percentage  *int                      `syno:"percentage"`
+	History     *int                      `syno:"history"`
+	Region      *string                   `syno:"region"`
+}
+
+// Validate checks that the camera is given and the source is known.
+func (s SurveillanceMotionSet) Validate() error {
+	if err := requireCamera(s.CameraID); err != nil {
+		return err
+	}
+	if s.Source != nil && !s.Source.Valid() {
+		return &ValidationError{Field: "Source", Reason: "unknown source"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceMotionSet) MarshalRequest() (*Request, error) {
+	r, err := surveillanceRequest(
+		surveillanceEventAPI, surveillanceEventVersion, "MDParamSave", s)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
+
+// Set returns the request applying the settings to a camera, such as to copy
+// them from one camera to others.
+func (p SurveillanceMotionParam) Set(cameraID int) SurveillanceMotionSet {
+	return SurveillanceMotionSet{
+		CameraID:    cameraID,
+		Source:      &p.Source,
+		Keep:        &p.Keep,
+		Sensitivity: &p.Sensitivity.Value,
+		Threshold:   &p.Threshold.Value,
+		ObjectSize:  &p.ObjectSize.Value,
+		Percentage:  &p.Percentage.Value,
+		History:     &p.History.Value,
+		Region:      &p.Region,
+	}
+}
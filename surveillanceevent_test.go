@@ -0,0 +1,89 @@
+package syno
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSurveillanceMotionGetMarshal(t *testing.T) {
+	r, err := SurveillanceMotionGet{CameraID: 2}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    surveillancePath,
+		API:     surveillanceEventAPI,
+		Version: surveillanceEventVersion,
+		Method:  "MotionEnum",
+		Params:  url.Values{"camId": []string{"2"}},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillanceMotionSettingsUnmarshal(t *testing.T) {
+	var res SurveillanceMotionSettings
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"MDParam": {
+			"source": 1,
+			"keep": false,
+			"sensitivity": {"value": 80, "min": 1, "max": 99},
+			"threshold": {"value": 20, "min": 1, "max": 99},
+			"region": "0,0,100,100"
+		}
+	}`), &res))
+	ensure.DeepEqual(t, res, SurveillanceMotionSettings{
+		Param: SurveillanceMotionParam{
+			Source:      SurveillanceMotionByServer,
+			Sensitivity: SurveillanceRange{Value: 80, Min: 1, Max: 99},
+			Threshold:   SurveillanceRange{Value: 20, Min: 1, Max: 99},
+			Region:      "0,0,100,100",
+		},
+	})
+}
+
+func TestSurveillanceMotionSetMarshal(t *testing.T) {
+	sensitivity := 70
+	source := SurveillanceMotionByCamera
+	r, err := SurveillanceMotionSet{
+		CameraID:    2,
+		Source:      &source,
+		Sensitivity: &sensitivity,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       surveillancePath,
+		API:        surveillanceEventAPI,
+		Version:    surveillanceEventVersion,
+		Method:     "MDParamSave",
+		HTTPMethod: http.MethodPost,
+		Params: url.Values{
+			"camId":       []string{"2"},
+			"source":      []string{"0"},
+			"sensitivity": []string{"70"},
+		},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillanceMotionParamSet(t *testing.T) {
+	p := SurveillanceMotionParam{
+		Source:      SurveillanceMotionByServer,
+		Sensitivity: SurveillanceRange{Value: 80},
+		Region:      "0,0,100,100",
+	}
+	r, err := p.Set(3).MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Params, url.Values{
+		"camId":       []string{"3"},
+		"source":      []string{"1"},
+		"keep":        []string{"false"},
+		"sensitivity": []string{"80"},
+		"threshold":   []string{"0"},
+		"objectSize":  []string{"0"},
+		"percentage":  []string{"0"},
+		"history":     []string{"0"},
+		"region":      []string{"0,0,100,100"},
+	})
+}
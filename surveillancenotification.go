@@ -0,0 +1,141 @@
+package syno
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	surveillanceNotificationFilterAPI     = "SYNO.SurveillanceStation.Notification.Filter"
+	surveillanceNotificationFilterVersion = "1"
+
+	surveillanceNotificationScheduleAPI     = "SYNO.SurveillanceStation.Notification.Schedule"
+	surveillanceNotificationScheduleVersion = "1"
+)
+
+// SurveillanceNotifyChannels is the set of channels an event is notified on.
+// The zero value notifies on none of them.
+type SurveillanceNotifyChannels int
+
+const (
+	SurveillanceNotifyEmail  = SurveillanceNotifyChannels(1 << 0)
+	SurveillanceNotifySMS    = SurveillanceNotifyChannels(1 << 1)
+	SurveillanceNotifyMobile = SurveillanceNotifyChannels(1 << 2)
+)
+
+// Has reports if the channel is in the set.
+func (c SurveillanceNotifyChannels) Has(channel SurveillanceNotifyChannels) bool {
+	return c&channel == channel
+}
+
+// SurveillanceNotificationFilterGet gets the channels each event is notified
+// on. The response is SurveillanceNotificationFilters.
+type SurveillanceNotificationFilterGet struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceNotificationFilterGet) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(surveillanceNotificationFilterAPI,
+		surveillanceNotificationFilterVersion, "Get", s)
+}
+
+// SurveillanceNotificationFilters maps the events, such as "CameraConnLost"
+// or "MotionDetected", to the channels they are notified on.
+type SurveillanceNotificationFilters map[string]SurveillanceNotifyChannels
+
+// SurveillanceNotificationFilterSet changes the channels events are notified
+// on. Events that are not given are not changed. It does not have a
+// response.
+type SurveillanceNotificationFilterSet struct {
+	Filters SurveillanceNotificationFilters
+}
+
+// Validate checks that at least one event is given.
+func (s SurveillanceNotificationFilterSet) Validate() error {
+	if len(s.Filters) == 0 {
+		return &ValidationError{Field: "Filters", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceNotificationFilterSet) MarshalRequest() (*Request, error) {
+	p := url.Values{}
+	for event, channels := range s.Filters {
+		p.Set(event, strconv.Itoa(int(channels)))
+	}
+	return &Request{
+		Path:       surveillancePath,
+		API:        surveillanceNotificationFilterAPI,
+		Version:    surveillanceNotificationFilterVersion,
+		Method:     "Set",
+		HTTPMethod: http.MethodPost,
+		Params:     p,
+		Session:    SessionSurveillanceStation,
+	}, nil
+}
+
+// SurveillanceNotificationScheduleGet gets when the events of a camera are
+// notified. The response is SurveillanceNotificationSchedule.
+type SurveillanceNotificationScheduleGet struct {
+	CameraID int `syno:"cameraId"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillanceNotificationScheduleGet) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceNotificationScheduleGet) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(surveillanceNotificationScheduleAPI,
+		surveillanceNotificationScheduleVersion, "GetCameraSchedule", s)
+}
+
+// SurveillanceNotificationSchedule is the response for
+// SurveillanceNotificationScheduleGet. Schedule has a row for every day of
+// the week starting on Sunday, each with 48 half hour slots that are 1 when
+// events are notified and 0 otherwise.
+type SurveillanceNotificationSchedule struct {
+	Schedule [][]int `json:"schedule"`
+}
+
+// SurveillanceNotificationScheduleSet changes when the events of a camera
+// are notified. It does not have a response.
+type SurveillanceNotificationScheduleSet struct {
+	CameraID int `syno:"cameraId"`
+
+	// Schedule is as in SurveillanceNotificationSchedule.
+	Schedule [][]int `syno:"schedule,json"`
+}
+
+// Validate checks that the camera is given and the schedule covers every half
+// hour of the week.
+func (s SurveillanceNotificationScheduleSet) Validate() error {
+	if err := requireCamera(s.CameraID); err != nil {
+		return err
+	}
+	if len(s.Schedule) != 7 {
+		return &ValidationError{Field: "Schedule", Reason: "must have 7 days"}
+	}
+	for _, day := range s.Schedule {
+		if len(day) != 48 {
+			return &ValidationError{
+				Field:  "Schedule",
+				Reason: "must have 48 half hours a day",
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceNotificationScheduleSet) MarshalRequest() (*Request, error) {
+	r, err := surveillanceRequest(surveillanceNotificationScheduleAPI,
+		surveillanceNotificationScheduleVersion, "SetCameraSchedule", s)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
@@ -0,0 +1,81 @@
+package syno
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSurveillanceNotifyChannelsHas(t *testing.T) {
+	c := SurveillanceNotifyEmail | SurveillanceNotifyMobile
+	ensure.True(t, c.Has(SurveillanceNotifyEmail))
+	ensure.True(t, c.Has(SurveillanceNotifyMobile))
+	ensure.False(t, c.Has(SurveillanceNotifySMS))
+	ensure.False(t, SurveillanceNotifyChannels(0).Has(SurveillanceNotifyEmail))
+}
+
+func TestSurveillanceNotificationFiltersUnmarshal(t *testing.T) {
+	var res SurveillanceNotificationFilters
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"CameraConnLost": 5,
+		"MotionDetected": 0
+	}`), &res))
+	ensure.DeepEqual(t, res, SurveillanceNotificationFilters{
+		"CameraConnLost": SurveillanceNotifyEmail | SurveillanceNotifyMobile,
+		"MotionDetected": 0,
+	})
+}
+
+func TestSurveillanceNotificationFilterSetMarshal(t *testing.T) {
+	r, err := SurveillanceNotificationFilterSet{
+		Filters: SurveillanceNotificationFilters{
+			"CameraConnLost": SurveillanceNotifyMobile,
+			"MotionDetected": 0,
+		},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       surveillancePath,
+		API:        surveillanceNotificationFilterAPI,
+		Version:    surveillanceNotificationFilterVersion,
+		Method:     "Set",
+		HTTPMethod: http.MethodPost,
+		Params: url.Values{
+			"CameraConnLost": []string{"4"},
+			"MotionDetected": []string{"0"},
+		},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillanceNotificationScheduleGetMarshal(t *testing.T) {
+	r, err := SurveillanceNotificationScheduleGet{CameraID: 2}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    surveillancePath,
+		API:     surveillanceNotificationScheduleAPI,
+		Version: surveillanceNotificationScheduleVersion,
+		Method:  "GetCameraSchedule",
+		Params:  url.Values{"cameraId": []string{"2"}},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillanceNotificationScheduleSetMarshal(t *testing.T) {
+	week := make([][]int, 7)
+	for i := range week {
+		week[i] = make([]int, 48)
+	}
+	week[0][0] = 1
+	r, err := SurveillanceNotificationScheduleSet{CameraID: 2, Schedule: week}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "SetCameraSchedule")
+	ensure.DeepEqual(t, r.HTTPMethod, http.MethodPost)
+	ensure.DeepEqual(t, r.Params.Get("cameraId"), "2")
+	ensure.True(t, strings.HasPrefix(r.Params.Get("schedule"), "[[1,0,"))
+	ensure.Nil(t, validate(SurveillanceNotificationScheduleSet{CameraID: 2, Schedule: week}))
+}
@@ -0,0 +1,164 @@
+package syno
+
+const (
+	surveillancePTZAPI     = "SYNO.SurveillanceStation.PTZ"
+	surveillancePTZVersion = "5"
+)
+
+func surveillancePTZRequest(method string, v interface{}) (*Request, error) {
+	return surveillanceRequest(
+		surveillancePTZAPI, surveillancePTZVersion, method, v)
+}
+
+// SurveillancePTZMove moves a PTZ camera. It does not have a response.
+type SurveillancePTZMove struct {
+	CameraID  int                      `syno:"cameraId"`
+	Direction SurveillancePTZDirection `syno:"direction"`
+
+	// Speed is from 1 to 5, and is left to the camera if zero.
+	Speed    int                     `syno:"speed,omitempty"`
+	MoveType SurveillancePTZMoveType `syno:"moveType,omitempty"`
+}
+
+// Validate checks that the camera is given and the direction and move type
+// are known.
+func (s SurveillancePTZMove) Validate() error {
+	if err := requireCamera(s.CameraID); err != nil {
+		return err
+	}
+	if !s.Direction.Valid() {
+		return &ValidationError{Field: "Direction", Reason: "unknown direction"}
+	}
+	if !s.MoveType.Valid() {
+		return &ValidationError{Field: "MoveType", Reason: "unknown move type"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillancePTZMove) MarshalRequest() (*Request, error) {
+	return surveillancePTZRequest("Move", s)
+}
+
+// SurveillancePTZZoom zooms a PTZ camera. It does not have a response.
+type SurveillancePTZZoom struct {
+	CameraID int                        `syno:"cameraId"`
+	Control  SurveillancePTZZoomControl `syno:"control"`
+	MoveType SurveillancePTZMoveType    `syno:"moveType,omitempty"`
+}
+
+// Validate checks that the camera is given and the zoom and move type are
+// known.
+func (s SurveillancePTZZoom) Validate() error {
+	if err := requireCamera(s.CameraID); err != nil {
+		return err
+	}
+	if !s.Control.Valid() {
+		return &ValidationError{Field: "Control", Reason: "must be in or out"}
+	}
+	if !s.MoveType.Valid() {
+		return &ValidationError{Field: "MoveType", Reason: "unknown move type"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillancePTZZoom) MarshalRequest() (*Request, error) {
+	return surveillancePTZRequest("Zoom", s)
+}
+
+// SurveillancePTZListPreset lists the preset positions of a PTZ camera. The
+// response is SurveillancePTZPresetListResponse.
+type SurveillancePTZListPreset struct {
+	CameraID int `syno:"cameraId"`
+	Offset   int `syno:"offset,omitempty"`
+	Limit    int `syno:"limit,omitempty"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillancePTZListPreset) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillancePTZListPreset) MarshalRequest() (*Request, error) {
+	return surveillancePTZRequest("ListPreset", s)
+}
+
+// SurveillancePTZPreset is a preset position of a PTZ camera.
+type SurveillancePTZPreset struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	Speed    int    `json:"speed"`
+}
+
+// SurveillancePTZPresetListResponse is the response from a
+// SurveillancePTZListPreset request.
+type SurveillancePTZPresetListResponse = ListResponse[SurveillancePTZPreset]
+
+// SurveillancePTZGoPreset moves a PTZ camera to a preset position. It does not
+// have a response.
+type SurveillancePTZGoPreset struct {
+	CameraID int `syno:"cameraId"`
+	PresetID int `syno:"presetId"`
+
+	// Speed is from 1 to 5, and is left to the camera if zero.
+	Speed int `syno:"speed,omitempty"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillancePTZGoPreset) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillancePTZGoPreset) MarshalRequest() (*Request, error) {
+	return surveillancePTZRequest("GoPreset", s)
+}
+
+// SurveillancePTZListPatrol lists the patrols of a PTZ camera, which move it
+// through a sequence of presets. The response is
+// SurveillancePTZPatrolListResponse.
+type SurveillancePTZListPatrol struct {
+	CameraID int `syno:"cameraId"`
+	Offset   int `syno:"offset,omitempty"`
+	Limit    int `syno:"limit,omitempty"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillancePTZListPatrol) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillancePTZListPatrol) MarshalRequest() (*Request, error) {
+	return surveillancePTZRequest("ListPatrol", s)
+}
+
+// SurveillancePTZPatrol is a patrol of a PTZ camera.
+type SurveillancePTZPatrol struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// SurveillancePTZPatrolListResponse is the response from a
+// SurveillancePTZListPatrol request.
+type SurveillancePTZPatrolListResponse = ListResponse[SurveillancePTZPatrol]
+
+// SurveillancePTZRunPatrol starts a patrol of a PTZ camera. It does not have a
+// response.
+type SurveillancePTZRunPatrol struct {
+	CameraID int `syno:"cameraId"`
+	PatrolID int `syno:"patrolId"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillancePTZRunPatrol) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillancePTZRunPatrol) MarshalRequest() (*Request, error) {
+	return surveillancePTZRequest("RunPatrol", s)
+}
@@ -0,0 +1,86 @@
+package syno
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSurveillancePTZMoveMarshal(t *testing.T) {
+	r, err := SurveillancePTZMove{
+		CameraID:  2,
+		Direction: SurveillancePTZLeft,
+		Speed:     3,
+		MoveType:  SurveillancePTZStart,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    surveillancePath,
+		API:     surveillancePTZAPI,
+		Version: surveillancePTZVersion,
+		Method:  "Move",
+		Params: url.Values{
+			"cameraId":  []string{"2"},
+			"direction": []string{"left"},
+			"speed":     []string{"3"},
+			"moveType":  []string{"Start"},
+		},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillancePTZRequestsMarshal(t *testing.T) {
+	cases := []struct {
+		Request MarshalRequest
+		Method  string
+		Params  url.Values
+	}{
+		{
+			Request: SurveillancePTZZoom{CameraID: 2, Control: SurveillancePTZZoomIn},
+			Method:  "Zoom",
+			Params:  url.Values{"cameraId": {"2"}, "control": {"in"}},
+		},
+		{
+			Request: SurveillancePTZListPreset{CameraID: 2},
+			Method:  "ListPreset",
+			Params:  url.Values{"cameraId": {"2"}},
+		},
+		{
+			Request: SurveillancePTZGoPreset{CameraID: 2, PresetID: 4},
+			Method:  "GoPreset",
+			Params:  url.Values{"cameraId": {"2"}, "presetId": {"4"}},
+		},
+		{
+			Request: SurveillancePTZListPatrol{CameraID: 2, Limit: 5},
+			Method:  "ListPatrol",
+			Params:  url.Values{"cameraId": {"2"}, "limit": {"5"}},
+		},
+		{
+			Request: SurveillancePTZRunPatrol{CameraID: 2, PatrolID: 1},
+			Method:  "RunPatrol",
+			Params:  url.Values{"cameraId": {"2"}, "patrolId": {"1"}},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r.API, surveillancePTZAPI, c.Method)
+		ensure.DeepEqual(t, r.Method, c.Method)
+		ensure.DeepEqual(t, r.Params, c.Params, c.Method)
+	}
+}
+
+func TestSurveillancePTZPresetListResponseUnmarshal(t *testing.T) {
+	var res SurveillancePTZPresetListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"total": 1,
+		"offset": 0,
+		"presets": [{"id": 4, "name": "Driveway", "position": 1, "speed": 3}]
+	}`), &res))
+	ensure.DeepEqual(t, res, SurveillancePTZPresetListResponse{
+		Total: 1,
+		Items: []SurveillancePTZPreset{{ID: 4, Name: "Driveway", Position: 1, Speed: 3}},
+	})
+}
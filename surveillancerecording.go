@@ -0,0 +1,324 @@
+package syno
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/daaku/syno/params"
+)
+
+const (
+	surveillanceRecordingAPI     = "SYNO.SurveillanceStation.Recording"
+	surveillanceRecordingVersion = "6"
+
+	surveillanceBookmarkAPI     = "SYNO.SurveillanceStation.Recording.Bookmark"
+	surveillanceBookmarkVersion = "1"
+)
+
+// setIntCSV sets the key to the comma separated numbers if there are any.
+func setIntCSV(p url.Values, key string, l []int) {
+	s := make([]string, len(l))
+	for i, n := range l {
+		s[i] = strconv.Itoa(n)
+	}
+	params.SetCSV(p, key, s)
+}
+
+// SurveillanceRecordingList lists the recordings, newest first. The response
+// is SurveillanceRecordingListResponse.
+type SurveillanceRecordingList struct {
+	Offset int
+	Limit  int
+
+	// CameraIDs lists only the recordings of these cameras.
+	CameraIDs []int
+
+	// From and To bound the time the recordings were made in, and are ignored
+	// if zero.
+	From time.Time
+	To   time.Time
+
+	// Locked lists only the recordings locked against deletion.
+	Locked bool
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceRecordingList) MarshalRequest() (*Request, error) {
+	p := url.Values{}
+	params.SetInt(p, "offset", s.Offset)
+	params.SetInt(p, "limit", s.Limit)
+	setIntCSV(p, "cameraIds", s.CameraIDs)
+	if !s.From.IsZero() {
+		params.SetInt64(p, "fromTime", s.From.Unix())
+	}
+	if !s.To.IsZero() {
+		params.SetInt64(p, "toTime", s.To.Unix())
+	}
+	if s.Locked {
+		p.Set("locked", "1")
+	}
+	return &Request{
+		Path:    surveillancePath,
+		API:     surveillanceRecordingAPI,
+		Version: surveillanceRecordingVersion,
+		Method:  "List",
+		Params:  p,
+		Session: SessionSurveillanceStation,
+	}, nil
+}
+
+// SurveillanceRecording is a recording as returned by
+// SurveillanceRecordingList. Recording is set while it is still being
+// recorded.
+type SurveillanceRecording struct {
+	ID         int                         `json:"id"`
+	CameraID   int                         `json:"cameraId"`
+	CameraName string                      `json:"camera_name"`
+	Start      Time                        `json:"startTime"`
+	Stop       Time                        `json:"stopTime"`
+	Reason     SurveillanceRecordingReason `json:"reason"`
+	Path       string                      `json:"path"`
+	FrameCount int                         `json:"frameCount"`
+	Recording  bool                        `json:"recording"`
+
+	// SizeMB is the size of the recording in megabytes.
+	SizeMB float64 `json:"eventSize"`
+}
+
+// SurveillanceRecordingListResponse is the response from a
+// SurveillanceRecordingList request.
+type SurveillanceRecordingListResponse = ListResponse[SurveillanceRecording]
+
+// Recordings lists the recordings selected by the request that were triggered
+// by any of the reasons, or all of them if none are given. The API does not
+// filter by reason itself, so the list is decoded as it is read and only the
+// selected recordings are kept. If the request has a Limit, further pages are
+// listed until Limit selected recordings are found or there are no more.
+func (c *Client) Recordings(
+	ctx context.Context,
+	l SurveillanceRecordingList,
+	reasons ...SurveillanceRecordingReason,
+) ([]SurveillanceRecording, error) {
+	var recordings []SurveillanceRecording
+	for {
+		var listed int
+		res, err := CallEach(ctx, c, l, func(r SurveillanceRecording) error {
+			listed++
+			if len(reasons) == 0 || containsReason(reasons, r.Reason) {
+				if l.Limit <= 0 || len(recordings) < l.Limit {
+					recordings = append(recordings, r)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		l.Offset += listed
+		// Pages may be shorter than Limit if the server caps their size, so
+		// only an empty page or reaching the total ends the listing.
+		if l.Limit <= 0 || len(recordings) >= l.Limit || listed == 0 ||
+			l.Offset >= res.Total {
+			return recordings, nil
+		}
+	}
+}
+
+func containsReason(l []SurveillanceRecordingReason, r SurveillanceRecordingReason) bool {
+	for _, v := range l {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
+
+// requireRecording returns a ValidationError if the recording ID is not given.
+func requireRecording(id int) error {
+	if id == 0 {
+		return &ValidationError{Field: "RecordingID", Reason: "required"}
+	}
+	return nil
+}
+
+// SurveillanceRecordingDownload downloads the video of a recording. The
+// response is a Stream, so it must be made with DoStream or by passing a
+// *Stream to Call.
+type SurveillanceRecordingDownload struct {
+	RecordingID int
+
+	// Offset skips the start of the recording, and Duration limits the length
+	// of the video downloaded. The whole recording is downloaded if both are
+	// zero.
+	Offset   time.Duration
+	Duration time.Duration
+}
+
+// Validate checks that the recording is given and the times are not
+// negative.
+func (s SurveillanceRecordingDownload) Validate() error {
+	if err := requireRecording(s.RecordingID); err != nil {
+		return err
+	}
+	if s.Offset < 0 {
+		return &ValidationError{Field: "Offset", Reason: "must not be negative"}
+	}
+	if s.Duration < 0 {
+		return &ValidationError{Field: "Duration", Reason: "must not be negative"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceRecordingDownload) MarshalRequest() (*Request, error) {
+	p := url.Values{"id": []string{strconv.Itoa(s.RecordingID)}}
+	params.SetInt64(p, "offsetTimeMs", s.Offset.Milliseconds())
+	params.SetInt64(p, "playTimeMs", s.Duration.Milliseconds())
+	return &Request{
+		Path:    surveillancePath,
+		API:     surveillanceRecordingAPI,
+		Version: surveillanceRecordingVersion,
+		Method:  "Download",
+		Params:  p,
+		Session: SessionSurveillanceStation,
+	}, nil
+}
+
+// DownloadRecording streams the video of the recording without buffering it.
+// The Stream reports the size and name of the file, and must be closed once
+// read.
+func (c *Client) DownloadRecording(
+	ctx context.Context,
+	d SurveillanceRecordingDownload,
+) (*Stream, error) {
+	var s Stream
+	if err := c.Call(ctx, d, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SurveillanceBookmarkSave bookmarks a moment in a recording, so it is marked
+// on the timeline for later review. The response is SurveillanceBookmark
+// with the ID of the new bookmark.
+type SurveillanceBookmarkSave struct {
+	CameraID    int
+	RecordingID int
+	Name        string
+	Comment     string
+
+	// Time is the moment to bookmark. It is the current time if zero.
+	Time time.Time
+}
+
+// Validate checks that the camera, recording and name are given.
+func (s SurveillanceBookmarkSave) Validate() error {
+	if err := requireCamera(s.CameraID); err != nil {
+		return err
+	}
+	if err := requireRecording(s.RecordingID); err != nil {
+		return err
+	}
+	return requireField("Name", s.Name)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceBookmarkSave) MarshalRequest() (*Request, error) {
+	t := s.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	p := url.Values{
+		"cameraId":  []string{strconv.Itoa(s.CameraID)},
+		"eventId":   []string{strconv.Itoa(s.RecordingID)},
+		"name":      []string{s.Name},
+		"timestamp": []string{strconv.FormatInt(t.Unix(), 10)},
+	}
+	params.SetString(p, "comment", s.Comment)
+	return &Request{
+		Path:       surveillancePath,
+		API:        surveillanceBookmarkAPI,
+		Version:    surveillanceBookmarkVersion,
+		Method:     "SaveBookmark",
+		HTTPMethod: http.MethodPost,
+		Params:     p,
+		Session:    SessionSurveillanceStation,
+	}, nil
+}
+
+// SurveillanceBookmarkList lists the bookmarks, newest first. The response is
+// SurveillanceBookmarkListResponse.
+type SurveillanceBookmarkList struct {
+	Offset int
+	Limit  int
+
+	// CameraIDs lists only the bookmarks of these cameras.
+	CameraIDs []int
+
+	// From and To bound the time of the bookmarks, and are ignored if zero.
+	From time.Time
+	To   time.Time
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceBookmarkList) MarshalRequest() (*Request, error) {
+	p := url.Values{}
+	params.SetInt(p, "offset", s.Offset)
+	params.SetInt(p, "limit", s.Limit)
+	setIntCSV(p, "cameraIds", s.CameraIDs)
+	if !s.From.IsZero() {
+		params.SetInt64(p, "fromTime", s.From.Unix())
+	}
+	if !s.To.IsZero() {
+		params.SetInt64(p, "toTime", s.To.Unix())
+	}
+	return &Request{
+		Path:    surveillancePath,
+		API:     surveillanceBookmarkAPI,
+		Version: surveillanceBookmarkVersion,
+		Method:  "ListBookmark",
+		Params:  p,
+		Session: SessionSurveillanceStation,
+	}, nil
+}
+
+// SurveillanceBookmark is a bookmark as returned by SurveillanceBookmarkList.
+type SurveillanceBookmark struct {
+	ID          int    `json:"id"`
+	CameraID    int    `json:"cameraId"`
+	RecordingID int    `json:"eventId"`
+	Name        string `json:"name"`
+	Comment     string `json:"comment"`
+	Time        Time   `json:"timestamp"`
+}
+
+// SurveillanceBookmarkListResponse is the response from a
+// SurveillanceBookmarkList request.
+type SurveillanceBookmarkListResponse = ListResponse[SurveillanceBookmark]
+
+// SurveillanceBookmarkDelete deletes bookmarks. It does not have a response.
+type SurveillanceBookmarkDelete struct {
+	IDs []int `syno:"bookmarkIds"`
+}
+
+// Validate checks that at least one bookmark is given.
+func (s SurveillanceBookmarkDelete) Validate() error {
+	if len(s.IDs) == 0 {
+		return &ValidationError{Field: "IDs", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceBookmarkDelete) MarshalRequest() (*Request, error) {
+	r, err := surveillanceRequest(surveillanceBookmarkAPI,
+		surveillanceBookmarkVersion, "DeleteBookmark", s)
+	if err != nil {
+		return nil, err
+	}
+	r.HTTPMethod = http.MethodPost
+	return r, nil
+}
@@ -0,0 +1,274 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/jsonpipe"
+)
+
+func TestSurveillanceRecordingListMarshal(t *testing.T) {
+	r, err := SurveillanceRecordingList{
+		Limit:     10,
+		CameraIDs: []int{1, 2},
+		From:      time.Unix(1500000000, 0),
+		To:        time.Unix(1500003600, 0),
+		Locked:    true,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    surveillancePath,
+		API:     surveillanceRecordingAPI,
+		Version: surveillanceRecordingVersion,
+		Method:  "List",
+		Params: url.Values{
+			"limit":     []string{"10"},
+			"cameraIds": []string{"1,2"},
+			"fromTime":  []string{"1500000000"},
+			"toTime":    []string{"1500003600"},
+			"locked":    []string{"1"},
+		},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestClientRecordings(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.FormValue("api"), surveillanceRecordingAPI)
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": true,
+					"data": {"total": 3, "offset": 0, "recordings": [
+						{"id": 1, "cameraId": 1, "reason": 1, "startTime": 1500000000},
+						{"id": 2, "cameraId": 1, "reason": 2, "startTime": 1500000100,
+						 "stopTime": 1500000200, "eventSize": 1.5},
+						{"id": 3, "cameraId": 2, "reason": 5}
+					]}
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	recordings, err := c.Recordings(
+		context.Background(),
+		SurveillanceRecordingList{},
+		SurveillanceRecordingMotion,
+		SurveillanceRecordingManual,
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, recordings, []SurveillanceRecording{
+		{
+			ID:       2,
+			CameraID: 1,
+			Reason:   SurveillanceRecordingMotion,
+			Start:    Time{time.Unix(1500000100, 0)},
+			Stop:     Time{time.Unix(1500000200, 0)},
+			SizeMB:   1.5,
+		},
+		{ID: 3, CameraID: 2, Reason: SurveillanceRecordingManual},
+	})
+}
+
+// recordingsClient serves the recordings in pages of at most max items,
+// recording the offsets requested.
+func recordingsClient(
+	t *testing.T,
+	all []map[string]int,
+	max int,
+	offsets *[]string,
+) *Client {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			*offsets = append(*offsets, r.FormValue("offset"))
+			offset, _ := strconv.Atoi(r.FormValue("offset"))
+			limit, _ := strconv.Atoi(r.FormValue("limit"))
+			if limit > max {
+				limit = max
+			}
+			end := offset + limit
+			if end > len(all) {
+				end = len(all)
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data": map[string]interface{}{
+						"total":      len(all),
+						"offset":     offset,
+						"recordings": all[offset:end],
+					},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+func TestClientRecordingsLimit(t *testing.T) {
+	all := []map[string]int{
+		{"id": 1, "reason": 2},
+		{"id": 2, "reason": 1},
+		{"id": 3, "reason": 1},
+		{"id": 4, "reason": 2},
+		{"id": 5, "reason": 2},
+	}
+	var offsets []string
+	c := recordingsClient(t, all, len(all), &offsets)
+	recordings, err := c.Recordings(
+		context.Background(),
+		SurveillanceRecordingList{Limit: 2},
+		SurveillanceRecordingMotion,
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, recordings, []SurveillanceRecording{
+		{ID: 1, Reason: SurveillanceRecordingMotion},
+		{ID: 4, Reason: SurveillanceRecordingMotion},
+	})
+	ensure.DeepEqual(t, offsets, []string{"", "2"})
+}
+
+func TestClientRecordingsShortPages(t *testing.T) {
+	all := []map[string]int{
+		{"id": 1, "reason": 1},
+		{"id": 2, "reason": 2},
+		{"id": 3, "reason": 1},
+		{"id": 4, "reason": 2},
+	}
+	var offsets []string
+	c := recordingsClient(t, all, 1, &offsets)
+	recordings, err := c.Recordings(
+		context.Background(),
+		SurveillanceRecordingList{Limit: 3},
+		SurveillanceRecordingMotion,
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, recordings, []SurveillanceRecording{
+		{ID: 2, Reason: SurveillanceRecordingMotion},
+		{ID: 4, Reason: SurveillanceRecordingMotion},
+	})
+	ensure.DeepEqual(t, offsets, []string{"", "1", "2", "3"})
+}
+
+func TestSurveillanceRecordingDownloadMarshal(t *testing.T) {
+	r, err := SurveillanceRecordingDownload{
+		RecordingID: 2,
+		Offset:      5 * time.Second,
+		Duration:    time.Minute,
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    surveillancePath,
+		API:     surveillanceRecordingAPI,
+		Version: surveillanceRecordingVersion,
+		Method:  "Download",
+		Params: url.Values{
+			"id":           []string{"2"},
+			"offsetTimeMs": []string{"5000"},
+			"playTimeMs":   []string{"60000"},
+		},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestClientDownloadRecording(t *testing.T) {
+	c := streamClient(t, func() *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Content-Type":        []string{"video/mp4"},
+				"Content-Disposition": []string{`attachment; filename="a.mp4"`},
+			},
+			ContentLength: 3,
+			Body:          ioutil.NopCloser(strings.NewReader("mp4")),
+		}
+	})
+	s, err := c.DownloadRecording(context.Background(), SurveillanceRecordingDownload{RecordingID: 2})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.Filename, "a.mp4")
+	b, err := ioutil.ReadAll(s)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), "mp4")
+	ensure.Nil(t, s.Close())
+}
+
+func TestSurveillanceBookmarkSaveMarshal(t *testing.T) {
+	r, err := SurveillanceBookmarkSave{
+		CameraID:    1,
+		RecordingID: 2,
+		Name:        "Doorbell",
+		Time:        time.Unix(1500000000, 0),
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       surveillancePath,
+		API:        surveillanceBookmarkAPI,
+		Version:    surveillanceBookmarkVersion,
+		Method:     "SaveBookmark",
+		HTTPMethod: http.MethodPost,
+		Params: url.Values{
+			"cameraId":  []string{"1"},
+			"eventId":   []string{"2"},
+			"name":      []string{"Doorbell"},
+			"timestamp": []string{"1500000000"},
+		},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillanceBookmarkListMarshal(t *testing.T) {
+	r, err := SurveillanceBookmarkList{
+		CameraIDs: []int{1},
+		From:      time.Unix(1500000000, 0),
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "ListBookmark")
+	ensure.DeepEqual(t, r.Params, url.Values{
+		"cameraIds": []string{"1"},
+		"fromTime":  []string{"1500000000"},
+	})
+}
+
+func TestSurveillanceBookmarkListResponseUnmarshal(t *testing.T) {
+	var res SurveillanceBookmarkListResponse
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"total": 1,
+		"bookmarks": [{
+			"id": 3,
+			"cameraId": 1,
+			"eventId": 2,
+			"name": "Doorbell",
+			"comment": "",
+			"timestamp": 1500000000
+		}]
+	}`), &res))
+	ensure.DeepEqual(t, res, SurveillanceBookmarkListResponse{
+		Total: 1,
+		Items: []SurveillanceBookmark{{
+			ID:          3,
+			CameraID:    1,
+			RecordingID: 2,
+			Name:        "Doorbell",
+			Time:        Time{time.Unix(1500000000, 0)},
+		}},
+	})
+}
+
+func TestSurveillanceBookmarkDeleteMarshal(t *testing.T) {
+	r, err := SurveillanceBookmarkDelete{IDs: []int{3, 4}}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "DeleteBookmark")
+	ensure.DeepEqual(t, r.HTTPMethod, http.MethodPost)
+	ensure.DeepEqual(t, r.Params, url.Values{"bookmarkIds": []string{"3,4"}})
+}
@@ -0,0 +1,287 @@
+package syno
+
+import "context"
+
+// Errors returned by the SYNO.SurveillanceStation APIs. Other APIs use the
+// same codes with different meanings.
+const (
+	ErrorSurveillanceExecutionFailed     = Error(400)
+	ErrorSurveillanceInvalidParameter    = Error(401)
+	ErrorSurveillanceCameraDisabled      = Error(402)
+	ErrorSurveillanceInsufficientLicense = Error(403)
+	ErrorSurveillanceCodecActivation     = Error(404)
+	ErrorSurveillanceCMSConnection       = Error(405)
+	ErrorSurveillanceCMSClosed           = Error(407)
+	ErrorSurveillanceMissingLicense      = Error(410)
+	ErrorSurveillanceNeedLicense         = Error(412)
+	ErrorSurveillancePlatformMaximum     = Error(413)
+	ErrorSurveillanceEventNotExist       = Error(414)
+	ErrorSurveillanceMessageConnect      = Error(415)
+	ErrorSurveillanceTestConnection      = Error(417)
+	ErrorSurveillanceObjectNotExist      = Error(418)
+	ErrorSurveillanceDuplicateName       = Error(419)
+	ErrorSurveillanceTooManyItems        = Error(439)
+)
+
+func init() {
+	RegisterAPIErrorStrings("SYNO.SurveillanceStation", map[Error]string{
+		ErrorSurveillanceExecutionFailed:     "execution failed",
+		ErrorSurveillanceInvalidParameter:    "parameter invalid",
+		ErrorSurveillanceCameraDisabled:      "camera disabled",
+		ErrorSurveillanceInsufficientLicense: "insufficient license",
+		ErrorSurveillanceCodecActivation:     "codec activation failed",
+		ErrorSurveillanceCMSConnection:       "CMS server connection failed",
+		ErrorSurveillanceCMSClosed:           "CMS closed",
+		ErrorSurveillanceMissingLicense:      "missing license",
+		ErrorSurveillanceNeedLicense:         "need to add license",
+		ErrorSurveillancePlatformMaximum:     "reached the maximum of platform",
+		ErrorSurveillanceEventNotExist:       "some events do not exist",
+		ErrorSurveillanceMessageConnect:      "message connect failed",
+		ErrorSurveillanceTestConnection:      "test connection error",
+		ErrorSurveillanceObjectNotExist:      "object or VisualStation ID does not exist",
+		ErrorSurveillanceDuplicateName:       "VisualStation name repetition",
+		ErrorSurveillanceTooManyItems:        "too many items selected",
+	})
+}
+
+const (
+	surveillancePath = "/webapi/entry.cgi"
+
+	surveillanceCameraAPI     = "SYNO.SurveillanceStation.Camera"
+	surveillanceCameraVersion = "9"
+
+	surveillanceHomeModeAPI     = "SYNO.SurveillanceStation.HomeMode"
+	surveillanceHomeModeVersion = "1"
+)
+
+// surveillanceRequest builds the Request for a SurveillanceStation API method
+// with the parameters from the fields of v.
+func surveillanceRequest(api, version, method string, v interface{}) (*Request, error) {
+	p, err := MarshalParams(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		Path:    surveillancePath,
+		API:     api,
+		Version: version,
+		Method:  method,
+		Params:  p,
+		Session: SessionSurveillanceStation,
+	}, nil
+}
+
+// requireCamera returns a ValidationError if the camera ID is not given.
+func requireCamera(id int) error {
+	if id == 0 {
+		return &ValidationError{Field: "CameraID", Reason: "required"}
+	}
+	return nil
+}
+
+// SurveillanceCameraEnable enables the cameras, so they record and stream
+// again. It does not have a response.
+type SurveillanceCameraEnable struct {
+	IDs []int `syno:"idList"`
+}
+
+// Validate checks that at least one camera is given.
+func (s SurveillanceCameraEnable) Validate() error {
+	if len(s.IDs) == 0 {
+		return &ValidationError{Field: "IDs", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraEnable) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(
+		surveillanceCameraAPI, surveillanceCameraVersion, "Enable", s)
+}
+
+// SurveillanceCameraDisable disables the cameras, which stops them recording
+// and streaming. It does not have a response.
+type SurveillanceCameraDisable struct {
+	IDs []int `syno:"idList"`
+}
+
+// Validate checks that at least one camera is given.
+func (s SurveillanceCameraDisable) Validate() error {
+	if len(s.IDs) == 0 {
+		return &ValidationError{Field: "IDs", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraDisable) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(
+		surveillanceCameraAPI, surveillanceCameraVersion, "Disable", s)
+}
+
+// SurveillanceCameraGetCapability gets what a camera supports. The response is
+// SurveillanceCameraCapability.
+type SurveillanceCameraGetCapability struct {
+	CameraID int `syno:"cameraId"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillanceCameraGetCapability) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraGetCapability) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(surveillanceCameraAPI,
+		surveillanceCameraVersion, "GetCapabilityByCamId", s)
+}
+
+// SurveillanceCameraCapability is the response for
+// SurveillanceCameraGetCapability. The PTZ movements are 0 if unsupported, 1
+// if the camera moves in steps and 2 if it moves continuously.
+type SurveillanceCameraCapability struct {
+	PTZPan       int  `json:"ptzPan"`
+	PTZTilt      int  `json:"ptzTilt"`
+	PTZZoom      int  `json:"ptzZoom"`
+	PTZFocus     int  `json:"ptzFocus"`
+	PTZIris      int  `json:"ptzIris"`
+	PTZSpeed     int  `json:"ptzSpeed"`
+	PTZDirection int  `json:"ptzDirection"`
+	PTZHome      bool `json:"ptzHome"`
+	PTZAbsolute  bool `json:"ptzAbs"`
+	PTZAutoFocus bool `json:"ptzAutoFocus"`
+	AudioOut     bool `json:"audioOut"`
+}
+
+// SurveillanceCameraGetSnapshot gets a JPEG of what a camera currently sees.
+// The response is a Stream, so it must be made with DoStream or by passing a
+// *Stream to Call.
+type SurveillanceCameraGetSnapshot struct {
+	CameraID int `syno:"id"`
+
+	// Profile sets the resolution of the snapshot, and is the high quality
+	// one by default. The API has no option for the size of the image in
+	// pixels, which is the resolution the camera is configured to stream the
+	// profile at, so a smaller image is requested by picking a lower profile.
+	Profile SurveillanceProfile `syno:"profileType"`
+}
+
+// Validate checks that the camera is given and the profile is known.
+func (s SurveillanceCameraGetSnapshot) Validate() error {
+	if err := requireCamera(s.CameraID); err != nil {
+		return err
+	}
+	if !s.Profile.Valid() {
+		return &ValidationError{Field: "Profile", Reason: "unknown profile"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraGetSnapshot) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(surveillanceCameraAPI,
+		surveillanceCameraVersion, "GetSnapshot", s)
+}
+
+// CameraSnapshot gets a JPEG of what the camera currently sees, at the
+// resolution of the given profile. The Stream reports the size of the image,
+// and must be closed once read.
+func (c *Client) CameraSnapshot(
+	ctx context.Context,
+	id int,
+	profile SurveillanceProfile,
+) (*Stream, error) {
+	var s Stream
+	err := c.Call(ctx, SurveillanceCameraGetSnapshot{CameraID: id, Profile: profile}, &s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SurveillanceCameraGetLiveViewPath gets the URLs of the live streams of the
+// cameras. The response is a []SurveillanceStreamPaths.
+type SurveillanceCameraGetLiveViewPath struct {
+	IDs []int `syno:"idList"`
+}
+
+// Validate checks that at least one camera is given.
+func (s SurveillanceCameraGetLiveViewPath) Validate() error {
+	if len(s.IDs) == 0 {
+		return &ValidationError{Field: "IDs", Reason: "required"}
+	}
+	return nil
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraGetLiveViewPath) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(surveillanceCameraAPI,
+		surveillanceCameraVersion, "GetLiveViewPath", s)
+}
+
+// SurveillanceCameraGetStreamPath gets the URLs of the live streams of a
+// single camera. The response is SurveillanceStreamPaths.
+type SurveillanceCameraGetStreamPath struct {
+	CameraID int `syno:"id"`
+}
+
+// Validate checks that the camera is given.
+func (s SurveillanceCameraGetStreamPath) Validate() error {
+	return requireCamera(s.CameraID)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceCameraGetStreamPath) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(surveillanceCameraAPI,
+		surveillanceCameraVersion, "GetStmUrlPath", s)
+}
+
+// SurveillanceStreamPaths are the URLs the live stream of a camera is served
+// at. They are empty for protocols the camera does not support.
+type SurveillanceStreamPaths struct {
+	ID           int    `json:"id"`
+	RTSP         string `json:"rtspPath"`
+	RTSPOverHTTP string `json:"rtspOverHttpPath"`
+	MJPEGHTTP    string `json:"mjpegHttpPath"`
+	MxPEGHTTP    string `json:"mxpegHttpPath"`
+	Multicast    string `json:"multicstPath"`
+}
+
+// CameraStreamPaths gets the URLs of the live streams of the cameras.
+func (c *Client) CameraStreamPaths(
+	ctx context.Context,
+	ids ...int,
+) ([]SurveillanceStreamPaths, error) {
+	return CallTyped[[]SurveillanceStreamPaths](
+		ctx, c, SurveillanceCameraGetLiveViewPath{IDs: ids})
+}
+
+// SurveillanceHomeModeGetInfo gets the Home Mode settings. The response is
+// SurveillanceHomeMode.
+type SurveillanceHomeModeGetInfo struct{}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceHomeModeGetInfo) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(
+		surveillanceHomeModeAPI, surveillanceHomeModeVersion, "GetInfo", s)
+}
+
+// SurveillanceHomeMode is the response for SurveillanceHomeModeGetInfo. On
+// reports if Home Mode is on, in which case the cameras follow the Home Mode
+// recording and notification settings, typically recording less while the
+// residents are home.
+type SurveillanceHomeMode struct {
+	On bool `json:"on"`
+}
+
+// SurveillanceHomeModeSwitch turns Home Mode on or off, such as when the
+// residents arrive or leave. It does not have a response.
+type SurveillanceHomeModeSwitch struct {
+	On bool `syno:"on"`
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (s SurveillanceHomeModeSwitch) MarshalRequest() (*Request, error) {
+	return surveillanceRequest(
+		surveillanceHomeModeAPI, surveillanceHomeModeVersion, "Switch", s)
+}
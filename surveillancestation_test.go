@@ -0,0 +1,150 @@
+package syno
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSurveillanceStationErrorStrings(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		ErrorString("SYNO.SurveillanceStation.Camera", ErrorSurveillanceNeedLicense),
+		"syno: need to add license (412)",
+	)
+	ensure.DeepEqual(
+		t,
+		ErrorString("SYNO.SurveillanceStation.Camera", ErrorSurveillanceCameraDisabled),
+		"syno: camera disabled (402)",
+	)
+}
+
+func TestSurveillanceCameraEnableMarshal(t *testing.T) {
+	r, err := SurveillanceCameraEnable{IDs: []int{1, 3}}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    surveillancePath,
+		API:     surveillanceCameraAPI,
+		Version: surveillanceCameraVersion,
+		Method:  "Enable",
+		Params:  url.Values{"idList": []string{"1,3"}},
+		Session: SessionSurveillanceStation,
+	})
+}
+
+func TestSurveillanceCameraDisableMarshal(t *testing.T) {
+	r, err := SurveillanceCameraDisable{IDs: []int{2}}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "Disable")
+	ensure.DeepEqual(t, r.Params, url.Values{"idList": []string{"2"}})
+}
+
+func TestSurveillanceCameraGetCapabilityMarshal(t *testing.T) {
+	r, err := SurveillanceCameraGetCapability{CameraID: 2}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "GetCapabilityByCamId")
+	ensure.DeepEqual(t, r.Params, url.Values{"cameraId": []string{"2"}})
+}
+
+func TestSurveillanceCameraCapabilityUnmarshal(t *testing.T) {
+	var res SurveillanceCameraCapability
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"ptzPan": 2,
+		"ptzTilt": 2,
+		"ptzZoom": 1,
+		"ptzHome": true,
+		"audioOut": false
+	}`), &res))
+	ensure.DeepEqual(t, res, SurveillanceCameraCapability{
+		PTZPan:  2,
+		PTZTilt: 2,
+		PTZZoom: 1,
+		PTZHome: true,
+	})
+}
+
+func TestClientCameraSnapshot(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.FormValue("api"), surveillanceCameraAPI)
+			ensure.DeepEqual(t, r.FormValue("method"), "GetSnapshot")
+			ensure.DeepEqual(t, r.FormValue("id"), "2")
+			ensure.DeepEqual(t, r.FormValue("profileType"), "2")
+			return &http.Response{
+				StatusCode:    200,
+				Header:        http.Header{"Content-Type": []string{"image/jpeg"}},
+				ContentLength: 4,
+				Body:          ioutil.NopCloser(strings.NewReader("\xff\xd8\xff\xe0")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	s, err := c.CameraSnapshot(context.Background(), 2, SurveillanceProfileLow)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.ContentType, "image/jpeg")
+	ensure.DeepEqual(t, s.ContentLength, int64(4))
+	ensure.Nil(t, s.Close())
+}
+
+func TestSurveillanceCameraGetStreamPathMarshal(t *testing.T) {
+	r, err := SurveillanceCameraGetStreamPath{CameraID: 2}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "GetStmUrlPath")
+	ensure.DeepEqual(t, r.Params, url.Values{"id": []string{"2"}})
+}
+
+func TestClientCameraStreamPaths(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.FormValue("method"), "GetLiveViewPath")
+			ensure.DeepEqual(t, r.FormValue("idList"), "1,2")
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": true,
+					"data": [
+						{"id": 1, "rtspPath": "rtsp://a/1", "multicstPath": "rtsp://m/1"},
+						{"id": 2, "mjpegHttpPath": "http://a/2"}
+					]
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	paths, err := c.CameraStreamPaths(context.Background(), 1, 2)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, paths, []SurveillanceStreamPaths{
+		{ID: 1, RTSP: "rtsp://a/1", Multicast: "rtsp://m/1"},
+		{ID: 2, MJPEGHTTP: "http://a/2"},
+	})
+}
+
+func TestSurveillanceHomeModeMarshal(t *testing.T) {
+	r, err := SurveillanceHomeModeGetInfo{}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    surveillancePath,
+		API:     surveillanceHomeModeAPI,
+		Version: surveillanceHomeModeVersion,
+		Method:  "GetInfo",
+		Params:  url.Values{},
+		Session: SessionSurveillanceStation,
+	})
+	r, err = SurveillanceHomeModeSwitch{}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Method, "Switch")
+	ensure.DeepEqual(t, r.Params, url.Values{"on": []string{"false"}})
+}
+
+func TestSurveillanceHomeModeUnmarshal(t *testing.T) {
+	var res SurveillanceHomeMode
+	ensure.Nil(t, json.Unmarshal([]byte(`{"on": true}`), &res))
+	ensure.DeepEqual(t, res, SurveillanceHomeMode{On: true})
+}
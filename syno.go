@@ -5,18 +5,25 @@
 package syno
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
-	"strconv"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 var (
 	errURLMisconfigured = errors.New("syno: client URL misconfigured")
+	errNoCredentials    = errors.New("syno: client credentials not configured")
 )
 
 // Error is the integer error code returned by the Synology API.
@@ -24,10 +31,7 @@ type Error int
 
 // Error returns a human readable error string for the error if one is known.
 func (e Error) Error() string {
-	if s, ok := errStrings[e]; ok {
-		return fmt.Sprint("syno: ", s, " (", int(e), ")")
-	}
-	return fmt.Sprintf("syno: error code %d", int(e))
+	return ErrorString("", e)
 }
 
 const (
@@ -41,34 +45,144 @@ const (
 	ErrorSessionInterruptedDuplicateLogin = Error(107)
 )
 
-var errStrings = map[Error]string{
-	ErrorUnknown:                          "unknown API error",
-	ErrorInvalidParameter:                 "invalid parameter",
-	ErrorInvalidAPI:                       "invalid API",
-	ErrorInvalidMethod:                    "invalid method",
-	ErrorUnsupportedVersion:               "unsupported version",
-	ErrorPermissionDenied:                 "permission denined",
-	ErrorSessionTimeout:                   "session timeout error",
-	ErrorSessionInterruptedDuplicateLogin: "session interrupted with duplicated login",
+var (
+	errStringsMu sync.RWMutex
+	errStrings   = map[Error]string{
+		ErrorUnknown:                          "unknown API error",
+		ErrorInvalidParameter:                 "invalid parameter",
+		ErrorInvalidAPI:                       "invalid API",
+		ErrorInvalidMethod:                    "invalid method",
+		ErrorUnsupportedVersion:               "unsupported version",
+		ErrorPermissionDenied:                 "permission denined",
+		ErrorSessionTimeout:                   "session timeout error",
+		ErrorSessionInterruptedDuplicateLogin: "session interrupted with duplicated login",
+	}
+	apiErrStrings = map[string]map[Error]string{}
+)
+
+// RegisterErrorStrings adds the given error strings to the global table used
+// by Error. Existing entries, including the built-in ones, are replaced which
+// allows for localizing them.
+func RegisterErrorStrings(m map[Error]string) {
+	errStringsMu.Lock()
+	defer errStringsMu.Unlock()
+	for e, s := range m {
+		errStrings[e] = s
+	}
+}
+
+// RegisterAPIErrorStrings adds the given error strings scoped to an API, such
+// as "SYNO.DownloadStation.Task". Many APIs reuse the same codes with
+// different meanings, so scoped strings take precedence over the global table.
+// A scope also applies to the APIs below it, so strings registered for
+// "SYNO.FileStation" apply to "SYNO.FileStation.List".
+func RegisterAPIErrorStrings(api string, m map[Error]string) {
+	errStringsMu.Lock()
+	defer errStringsMu.Unlock()
+	scoped := apiErrStrings[api]
+	if scoped == nil {
+		scoped = make(map[Error]string, len(m))
+		apiErrStrings[api] = scoped
+	}
+	for e, s := range m {
+		scoped[e] = s
+	}
 }
 
-func dropEmpty(p url.Values) url.Values {
-	for k, v := range p {
-		if len(v) == 1 && v[0] == "" {
-			p.Del(k)
+// LookupErrorString returns the registered message for the error as returned
+// by the given API, without the code or prefix ErrorString adds. It reports
+// false if there is no message for the error, which allows packages
+// registering their own strings to detect existing ones.
+func LookupErrorString(api string, e Error) (string, bool) {
+	errStringsMu.RLock()
+	defer errStringsMu.RUnlock()
+	for api != "" {
+		if s, ok := apiErrStrings[api][e]; ok {
+			return s, true
+		}
+		i := strings.LastIndexByte(api, '.')
+		if i < 0 {
+			break
 		}
+		api = api[:i]
 	}
-	return p
+	s, ok := errStrings[e]
+	return s, ok
 }
 
+// ErrorString returns a human readable error string for the error as returned
+// by the given API. It prefers strings registered for the API and falls back
+// to the global table.
+func ErrorString(api string, e Error) string {
+	if s, ok := LookupErrorString(api, e); ok {
+		return fmt.Sprint("syno: ", s, " (", int(e), ")")
+	}
+	return fmt.Sprintf("syno: error code %d", int(e))
+}
+
+// Names of the DSM application sessions. Some DSM setups scope sessions per
+// application, in which case requests for an application must use a "sid"
+// obtained by logging into the matching session.
+const (
+	SessionDownloadStation     = "DownloadStation"
+	SessionFileStation         = "FileStation"
+	SessionSurveillanceStation = "SurveillanceStation"
+)
+
+// entryPath is the unified endpoint DSM 6 and later route most APIs through.
+const entryPath = "/webapi/entry.cgi"
+
+// maxGetURLLength is the longest URL sent as a GET request when the
+// HTTPMethod is not specified. Longer ones are sent as POST instead, since the
+// DSM web server rejects overly long request lines.
+const maxGetURLLength = 4096
+
 // Request represents an API request to Synology.
 type Request struct {
+	// Path is the CGI path the API is served from. If empty, the unified
+	// "/webapi/entry.cgi" endpoint is used.
 	Path    string
 	API     string
 	Version string
 	Method  string
 	Params  url.Values
 	SID     string
+
+	// MinVersion and MaxVersion specify the range of versions the request is
+	// compatible with. If Version is empty and either is set, the Client picks
+	// the highest version in the range supported by the server, using the
+	// cached response of an APIInfo request. If Path is empty, the path
+	// reported by the server is used as well.
+	MinVersion int
+	MaxVersion int
+
+	// HTTPMethod is the HTTP method used to send the request. It defaults to
+	// GET, which sends the parameters in the query string. POST sends them as
+	// an application/x-www-form-urlencoded body instead, which some APIs require
+	// for long parameter values. If empty, requests whose URL would be too long
+	// are sent as POST automatically.
+	HTTPMethod string
+
+	// Timeout bounds the time the request may take, including any retries. It
+	// overrides the default configured with ClientTimeout.
+	Timeout time.Duration
+
+	// Files are sent in a multipart/form-data POST body along with the other
+	// parameters, regardless of the HTTPMethod.
+	Files []RequestFile
+
+	// Header holds additional headers to send with the request, such as
+	// cookies. They replace the headers the Client would otherwise send.
+	Header http.Header
+
+	// Cacheable marks idempotent read requests, whose responses are cached if
+	// the Client was configured with ClientCache.
+	Cacheable bool
+
+	// Session is the name of the application session the request belongs to.
+	// If SID is not set, the "sid" for the named session is used, logging into
+	// it on demand if the Client was configured with ClientCredentials.
+	Session string
 }
 
 // MarshalRequest can be implemented by a type that can be serialized to a
@@ -77,20 +191,87 @@ type MarshalRequest interface {
 	MarshalRequest() (*Request, error)
 }
 
-// Client provides access to the Synology API.
+// clientOptions holds the configuration set by ClientOptions. It does not
+// change once the Client has been created.
+type clientOptions struct {
+	url                *url.URL
+	transport          http.RoundTripper
+	httpClient         *http.Client
+	ownTransport       bool
+	jar                http.CookieJar
+	credentials        *AuthLogin
+	autoRelogin        bool
+	retry              *RetryPolicy
+	timeout            time.Duration
+	debug              io.Writer
+	logger             *slog.Logger
+	metrics            Metrics
+	keepAlive          time.Duration
+	sessionStore       SessionStore
+	disableCompression bool
+	cache              Cache
+	cacheTTL           time.Duration
+	session            string
+	restored           bool
+
+	apiInfo        *apiInfoCache
+	pinnedVersions map[string]int
+}
+
+// Client provides access to the Synology API. It is safe for concurrent use.
 type Client struct {
-	url       *url.URL
-	transport http.RoundTripper
-	sid       string
+	clientOptions
+
+	mu         sync.Mutex
+	sid        string
+	sessions   map[string]string
+	synoTokens map[string]string
+
+	stopKeepAlive chan struct{}
+	closeOnce     sync.Once
+}
+
+// SID returns the default "sid" used for requests without a SID or Session.
+func (c *Client) SID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sid
+}
+
+// SetSID replaces the default "sid" used for requests without a SID or
+// Session.
+func (c *Client) SetSID(sid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sid = sid
+}
+
+// WithSID returns a copy of the Client which uses the given "sid" by default.
+// The copy shares the configuration and transport of the Client, but none of
+// its named sessions or credentials, so it never logs in as the original
+// account. This makes it cheap to act on behalf of many sessions at once.
+func (c *Client) WithSID(sid string) *Client {
+	n := &Client{clientOptions: c.clientOptions, sid: sid}
+	n.credentials = nil
+	n.autoRelogin = false
+	n.keepAlive = 0
+	n.sessionStore = nil
+	if token := c.synoToken(sid); token != "" {
+		n.synoTokens = map[string]string{sid: token}
+	}
+	return n
 }
 
 // Call makes a request obtained from marshaling the given argument and calls
-// Do with it.
+// Do with it. If the argument implements Validator, it is validated first.
 func (c *Client) Call(
 	ctx context.Context,
 	r MarshalRequest,
 	data interface{},
 ) error {
+	if err := validate(r); err != nil {
+		return err
+	}
 	req, err := r.MarshalRequest()
 	if err != nil {
 		return err
@@ -98,59 +279,597 @@ func (c *Client) Call(
 	return c.Do(ctx, req, data)
 }
 
+// CallTyped is like Call, except the response is unmarshaled into and returned
+// as a value of type T.
+func CallTyped[T any](ctx context.Context, c *Client, r MarshalRequest) (T, error) {
+	var data T
+	if err := c.Call(ctx, r, &data); err != nil {
+		var zero T
+		return zero, err
+	}
+	return data, nil
+}
+
+// DoTyped is like Do, except the response is unmarshaled into and returned as
+// a value of type T.
+func DoTyped[T any](ctx context.Context, c *Client, r *Request) (T, error) {
+	var data T
+	if err := c.Do(ctx, r, &data); err != nil {
+		var zero T
+		return zero, err
+	}
+	return data, nil
+}
+
+// requestSID returns the "sid" to use for the request.
+func (c *Client) requestSID(ctx context.Context, r *Request) (string, error) {
+	if r.SID != "" {
+		return r.SID, nil
+	}
+	c.mu.Lock()
+	sid, ok := c.sessions[r.Session]
+	if r.Session == "" || (!ok && c.credentials == nil) {
+		sid, ok = c.sid, true
+	}
+	c.mu.Unlock()
+	if ok {
+		return sid, nil
+	}
+	return c.LoginSession(ctx, r.Session)
+}
+
+// storeLogin records the "sid" and SynoToken obtained by logging into the
+// named session. If def is true the "sid" also becomes the default for the
+// Client, and is saved to the SessionStore if one is configured.
+func (c *Client) storeLogin(session string, def bool, res *AuthLoginResponse) error {
+	c.mu.Lock()
+	c.storeLoginLocked(session, def, res)
+	c.mu.Unlock()
+	if def && c.sessionStore != nil {
+		return c.sessionStore.Save(&StoredSession{
+			SID:       res.SID,
+			SynoToken: res.SynoToken,
+		})
+	}
+	return nil
+}
+
+func (c *Client) storeLoginLocked(session string, def bool, res *AuthLoginResponse) {
+	if def {
+		c.sid = res.SID
+	}
+	if session != "" {
+		if c.sessions == nil {
+			c.sessions = make(map[string]string)
+		}
+		c.sessions[session] = res.SID
+	}
+	if res.SynoToken != "" {
+		if c.synoTokens == nil {
+			c.synoTokens = make(map[string]string)
+		}
+		c.synoTokens[res.SID] = res.SynoToken
+	}
+}
+
+// loginSession returns the session to log into, defaulting to the one
+// configured with ClientSession.
+func (c *Client) loginSession(session string) string {
+	if session == "" {
+		return c.session
+	}
+	return session
+}
+
+// synoToken returns the SynoToken issued along with the "sid", if any.
+func (c *Client) synoToken(sid string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.synoTokens[sid]
+}
+
+// LoginSession logs into the named application session using the credentials
+// configured via ClientCredentials, and returns the resulting "sid". The
+// "sid" is used for subsequent requests with a matching Session.
+func (c *Client) LoginSession(ctx context.Context, session string) (string, error) {
+	c.mu.Lock()
+	creds := c.credentials
+	c.mu.Unlock()
+	if creds == nil {
+		return "", errNoCredentials
+	}
+	l := *creds
+	l.Session = session
+	l.Format = "sid"
+	var res AuthLoginResponse
+	if err := c.Call(ctx, l, &res); err != nil {
+		return "", err
+	}
+	c.storeLogin(session, false, &res)
+	return res.SID, nil
+}
+
+// Login logs in using the credentials configured via ClientCredentials or
+// ClientAutoRelogin, and sets the resulting "sid" as the default for the
+// Client.
+func (c *Client) Login(ctx context.Context) error {
+	c.mu.Lock()
+	creds := c.credentials
+	c.mu.Unlock()
+	if creds == nil {
+		return errNoCredentials
+	}
+	l := *creds
+	l.Session = c.loginSession(l.Session)
+	l.Format = "sid"
+	var res AuthLoginResponse
+	if err := c.Call(ctx, l, &res); err != nil {
+		return err
+	}
+	return c.storeLogin(l.Session, true, &res)
+}
+
+// Close logs out the default and all named sessions held by the Client and
+// clears them, and stops the keep-alive if one was configured. The Client can
+// still be used afterwards, and will log in again on demand if it was
+// configured with credentials.
+func (c *Client) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		if c.stopKeepAlive != nil {
+			close(c.stopKeepAlive)
+		}
+	})
+
+	c.mu.Lock()
+	sid, sessions := c.sid, c.sessions
+	c.sid, c.sessions, c.synoTokens = "", nil, nil
+	c.mu.Unlock()
+	var first error
+	if sid != "" && c.sessionStore != nil {
+		first = c.sessionStore.Save(&StoredSession{})
+	}
+
+	logout := func(l AuthLogout) {
+		if err := c.Call(ctx, l, nil); err != nil && first == nil {
+			first = err
+		}
+	}
+	done := make(map[string]bool, len(sessions)+1)
+	for session, s := range sessions {
+		if !done[s] {
+			done[s] = true
+			logout(AuthLogout{Session: session, SID: s})
+		}
+	}
+	if sid != "" && !done[sid] {
+		logout(AuthLogout{Session: c.session, SID: sid})
+	}
+	return first
+}
+
+// isSessionError reports if the error indicates the "sid" is no longer valid.
+func isSessionError(err error) bool {
+	return errors.Is(err, ErrorSessionTimeout) ||
+		errors.Is(err, ErrorSessionInterruptedDuplicateLogin)
+}
+
+// relogin logs into the session the request belongs to again.
+func (c *Client) relogin(ctx context.Context, r *Request) error {
+	if r.Session != "" {
+		c.mu.Lock()
+		_, ok := c.sessions[r.Session]
+		c.mu.Unlock()
+		if ok {
+			_, err := c.LoginSession(ctx, r.Session)
+			return err
+		}
+	}
+	return c.Login(ctx)
+}
+
+// newHTTPRequest builds the HTTP request for r, encoding the parameters as a
+// form or along with the Files in a multipart body.
+func (c *Client) newHTTPRequest(
+	ctx context.Context,
+	r *Request,
+	sid string,
+	token string,
+) (*http.Request, error) {
+	path := r.Path
+	if path == "" {
+		path = entryPath
+	}
+	u := c.url.ResolveReference(&url.URL{Path: path})
+	var hreq *http.Request
+	var err error
+	if len(r.Files) > 0 {
+		hreq, err = newMultipartRequest(ctx, u, r, sid, token)
+	} else {
+		hreq, err = newFormRequest(ctx, u, r, sid, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		hreq.Header.Set("X-SYNO-TOKEN", token)
+	}
+	return hreq, nil
+}
+
+// newFormRequest builds a request with the parameters url encoded in the query
+// string or the request body depending on the HTTPMethod.
+func newFormRequest(
+	ctx context.Context,
+	u *url.URL,
+	r *Request,
+	sid string,
+	token string,
+) (*http.Request, error) {
+	query := encodeQuery(r, sid, token)
+	method := r.HTTPMethod
+	if method == "" {
+		method = http.MethodGet
+		if len(u.String())+1+len(query) > maxGetURLLength {
+			method = http.MethodPost
+		}
+	}
+	switch method {
+	case http.MethodGet:
+		u.RawQuery = query
+		return http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	case http.MethodPost:
+		hreq, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodPost,
+			u.String(),
+			strings.NewReader(query),
+		)
+		if err != nil {
+			return nil, err
+		}
+		hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return hreq, nil
+	}
+	return nil, fmt.Errorf("syno: unsupported HTTP method %q", r.HTTPMethod)
+}
+
 // Do performs an API request and unmarshals the "Data" into the passed in
-// argument. If data is nil, it is ignored.
+// argument. If data is nil, it is ignored. The context is attached to the
+// underlying HTTP request, so cancelling it aborts the in-flight call.
+//
+// If the Client was configured with ClientAutoRelogin, requests failing with
+// an expired session are retried once after logging in again. If it was
+// configured with ClientRetry, transient failures are retried according to
+// the RetryPolicy.
 func (c *Client) Do(ctx context.Context, r *Request, data interface{}) error {
-	v := make(url.Values)
-	v.Add("api", r.API)
-	v.Add("version", r.Version)
-	v.Add("method", r.Method)
+	timeout := c.timeout
+	if r.Timeout > 0 {
+		timeout = r.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		if s, ok := data.(*Stream); ok {
+			// The body of a successful stream outlives the call, so it is only
+			// cancelled once the stream is closed.
+			defer func() {
+				if s.ReadCloser == nil {
+					cancel()
+					return
+				}
+				s.cancel = cancel
+			}()
+		} else {
+			defer cancel()
+		}
+	}
 
-	if r.SID != "" {
-		v.Add("_sid", r.SID)
-	} else if c.sid != "" {
-		v.Add("_sid", c.sid)
+	r, err := c.negotiate(ctx, withContextParams(ctx, r))
+	if err != nil {
+		return err
+	}
+	if c.cache != nil && r.Cacheable {
+		return c.doCached(ctx, r, data)
+	}
+	return c.send(ctx, r, data)
+}
+
+// send performs the request, retrying it and recording metrics and logs as
+// configured.
+func (c *Client) send(ctx context.Context, r *Request, data interface{}) error {
+	if c.metrics != nil {
+		c.metrics.RequestStarted(r.API, r.Method)
+	}
+	start := time.Now()
+	var err error
+	if c.retry != nil && len(r.Files) == 0 {
+		err = c.retry.do(ctx, r, func() error { return c.doRelogin(ctx, r, data) })
+	} else {
+		err = c.doRelogin(ctx, r, data)
+	}
+	if te, ok := err.(*transportError); ok {
+		err = te.err
+	}
+	err = wrapAPIError(r, err)
+	d := time.Since(start)
+	if c.metrics != nil {
+		c.metrics.RequestFinished(r.API, r.Method, d, err)
+	}
+	if c.logger != nil {
+		c.logRequest(ctx, r, d, err)
+	}
+	return err
+}
+
+// Response is the envelope every API response is wrapped in.
+type Response struct {
+	Success bool            `json:"success"`
+	Error   ResponseError   `json:"error"`
+	Data    json.RawMessage `json:"data"`
+
+	// Body is the raw response body the envelope was decoded from.
+	Body []byte `json:"-"`
+}
+
+// ResponseError is the error part of a Response. It is returned as the error
+// instead of the Code alone when the response includes details about the
+// individual items that failed.
+type ResponseError struct {
+	Code Error `json:"code"`
+
+	// Errors holds the additional details some APIs include, such as the
+	// paths a FileStation request failed for.
+	Errors json.RawMessage `json:"errors,omitempty"`
+
+	// Details are the entries decoded from Errors, if it is an array.
+	Details []ErrorDetail `json:"-"`
+
+	// API is the API that reported the error, whose registered error strings
+	// are used to describe the codes. It is set by Do.
+	API string `json:"-"`
+}
+
+// ErrorDetail is an entry in the nested "errors" of a ResponseError.
+type ErrorDetail struct {
+	Code Error  `json:"code"`
+	Path string `json:"path,omitempty"`
+
+	// Raw is the entry as returned by the API, including any other fields.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the error along with its Details.
+func (e *ResponseError) UnmarshalJSON(b []byte) error {
+	type responseError ResponseError
+	if err := json.Unmarshal(b, (*responseError)(e)); err != nil {
+		return err
+	}
+	e.Details = nil
+	var raw []json.RawMessage
+	if json.Unmarshal(e.Errors, &raw) != nil {
+		return nil
+	}
+	for _, r := range raw {
+		var d ErrorDetail
+		if json.Unmarshal(r, &d) != nil {
+			continue
+		}
+		d.Raw = r
+		e.Details = append(e.Details, d)
+	}
+	return nil
+}
+
+func (e *ResponseError) Error() string { return e.message(e.API) }
+
+// message describes the code and details using the error strings of the API.
+func (e *ResponseError) message(api string) string {
+	var b strings.Builder
+	b.WriteString(ErrorString(api, e.Code))
+	for i, d := range e.Details {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(d.Path)
+		if d.Code != 0 {
+			if d.Path != "" {
+				b.WriteString(": ")
+			}
+			b.WriteString(strings.TrimPrefix(ErrorString(api, d.Code), "syno: "))
+		}
 	}
+	return b.String()
+}
+
+// Unwrap returns the Code, so errors.Is can be used to check for it.
+func (e *ResponseError) Unwrap() error { return e.Code }
+
+// err returns the error for an unsuccessful response.
+func (e ResponseError) err() error {
+	if len(e.Details) == 0 {
+		return e.Code
+	}
+	return &e
+}
+
+// DoRaw performs an API request like Do, but returns the decoded envelope
+// instead of unmarshaling the "Data". The Response is returned along with the
+// error for unsuccessful requests, and if the body could not be decoded it
+// still holds the raw Body. Passing a *Response as the data to Do has the same
+// effect.
+func (c *Client) DoRaw(ctx context.Context, r *Request) (*Response, error) {
+	var res Response
+	err := c.Do(ctx, r, &res)
+	return &res, err
+}
 
-	for k, l := range r.Params {
-		for _, e := range l {
-			v.Add(k, e)
+func (c *Client) doRelogin(ctx context.Context, r *Request, data interface{}) error {
+	err := c.do(ctx, r, data)
+	if c.autoRelogin && r.SID == "" && r.API != authLoginAPI && len(r.Files) == 0 &&
+		isSessionError(err) {
+		if err := c.relogin(ctx, r); err != nil {
+			return err
 		}
+		return c.do(ctx, r, data)
 	}
+	return err
+}
 
-	hreq := (&http.Request{
-		Method: "GET",
-		URL: c.url.ResolveReference(&url.URL{
-			Path:     r.Path,
-			RawQuery: v.Encode(),
-		}),
-		Header: make(http.Header),
-	}).WithContext(ctx)
+// roundTrip sends the HTTP request using the configured http.Client, or
+// directly using the transport if there is none, attaching and storing
+// cookies if the Client has a cookie jar.
+func (c *Client) roundTrip(hreq *http.Request) (*http.Response, error) {
+	if c.httpClient != nil {
+		hc := *c.httpClient
+		hc.Transport = c.transport
+		if hc.Jar == nil {
+			hc.Jar = c.jar
+		}
+		return hc.Do(hreq)
+	}
+	if c.jar != nil {
+		for _, cookie := range c.jar.Cookies(hreq.URL) {
+			hreq.AddCookie(cookie)
+		}
+	}
 	hres, err := c.transport.RoundTrip(hreq)
+	if err != nil {
+		return nil, err
+	}
+	if c.jar != nil {
+		if cookies := hres.Cookies(); len(cookies) > 0 {
+			c.jar.SetCookies(hreq.URL, cookies)
+		}
+	}
+	return hres, nil
+}
+
+func (c *Client) do(ctx context.Context, r *Request, data interface{}) error {
+	sid, err := c.requestSID(ctx, r)
 	if err != nil {
 		return err
 	}
-	defer hres.Body.Close()
 
-	var synologyResponse struct {
-		Success bool
-		Error   struct{ Code Error }
-		Data    json.RawMessage
+	hreq, err := c.newHTTPRequest(ctx, r, sid, c.synoToken(sid))
+	if err != nil {
+		return err
+	}
+	s, isStream := data.(*Stream)
+	if !isStream && !c.disableCompression {
+		hreq.Header.Set("Accept-Encoding", "gzip")
+	}
+	setHeaders(ctx, hreq, r)
+	if c.debug != nil {
+		c.debugRequest(hreq)
 	}
-	if err := json.NewDecoder(hres.Body).Decode(&synologyResponse); err != nil {
+	hres, err := c.roundTrip(hreq)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	if isStream {
+		return c.stream(hreq, hres, s)
+	}
+	if err := decompress(hres); err != nil {
+		return &transportError{err: err}
+	}
+	defer hres.Body.Close()
+	if c.debug != nil {
+		if err := c.debugResponse(hres); err != nil {
+			return &transportError{err: err}
+		}
+	}
+	if err := newHTTPError(hres); err != nil {
 		return err
 	}
+
+	if raw, ok := data.(*Response); ok {
+		body, err := io.ReadAll(hres.Body)
+		if err != nil {
+			return &transportError{err: err}
+		}
+		*raw = Response{Body: body}
+		if err := json.Unmarshal(body, raw); err != nil {
+			return newDecodeError(hreq, body, err)
+		}
+		if !raw.Success {
+			return raw.Error.err()
+		}
+		return nil
+	}
+
+	body := &prefixReader{r: hres.Body}
+	if d, ok := data.(dataDecoder); ok {
+		err := decodeStreaming(body, d)
+		switch err.(type) {
+		case nil, Error, *ResponseError, *callbackError:
+			return err
+		}
+		return newDecodeError(hreq, body.prefix, err)
+	}
+
+	var synologyResponse Response
+	if err := json.NewDecoder(body).Decode(&synologyResponse); err != nil {
+		return newDecodeError(hreq, body.prefix, err)
+	}
 	if !synologyResponse.Success {
-		return synologyResponse.Error.Code
+		return synologyResponse.Error.err()
 	}
 	if data != nil {
 		if err := json.Unmarshal(synologyResponse.Data, data); err != nil {
-			return err
+			return newDecodeError(hreq, body.prefix, err)
 		}
 	}
 	return nil
 }
 
+var queryBufPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func writeQueryParam(b *bytes.Buffer, k, v string) {
+	if b.Len() > 0 {
+		b.WriteByte('&')
+	}
+	b.WriteString(url.QueryEscape(k))
+	b.WriteByte('=')
+	b.WriteString(url.QueryEscape(v))
+}
+
+// encodeQuery encodes the query string for the request directly from its
+// fields and Params, avoiding the intermediate url.Values a call to
+// url.Values.Encode would require. Params are encoded in sorted key order.
+func encodeQuery(r *Request, sid, token string) string {
+	b := queryBufPool.Get().(*bytes.Buffer)
+	b.Reset()
+	defer queryBufPool.Put(b)
+
+	writeQueryParam(b, "api", r.API)
+	writeQueryParam(b, "version", r.Version)
+	writeQueryParam(b, "method", r.Method)
+	if sid != "" {
+		writeQueryParam(b, "_sid", sid)
+	}
+	if token != "" {
+		writeQueryParam(b, "SynoToken", token)
+	}
+
+	if len(r.Params) > 0 {
+		keys := make([]string, 0, len(r.Params))
+		for k := range r.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, e := range r.Params[k] {
+				writeQueryParam(b, k, e)
+			}
+		}
+	}
+	return b.String()
+}
+
 // ClientOption allows configuring various aspects of the Client.
 type ClientOption func(*Client) error
 
@@ -178,6 +897,33 @@ func ClientRawURL(u string) ClientOption {
 func ClientTransport(t http.RoundTripper) ClientOption {
 	return func(c *Client) error {
 		c.transport = t
+		c.ownTransport = false
+		return nil
+	}
+}
+
+// ClientHTTPClient configures the http.Client used to send requests, along
+// with its redirect policy, cookie jar and timeout. Its Transport is used as
+// if given to ClientTransport, and can be configured further by the other
+// transport options without modifying the given http.Client.
+func ClientHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) error {
+		clone := *hc
+		c.httpClient = &clone
+		c.transport = hc.Transport
+		if c.transport == nil {
+			c.transport = http.DefaultTransport
+		}
+		c.ownTransport = false
+		return nil
+	}
+}
+
+// ClientTimeout configures the default time a request may take, including any
+// retries. It can be overridden per Request.
+func ClientTimeout(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.timeout = d
 		return nil
 	}
 }
@@ -194,21 +940,93 @@ func ClientSID(sid string) ClientOption {
 // ClientLogin configures the Client with a "sid" from the given credentials.
 // It does so when the client is being initialized, so the ordering of this
 // option should typically be after all the other options have been specified.
+// If a session was loaded from a SessionStore, it is used instead of logging
+// in again.
 func ClientLogin(l AuthLogin) ClientOption {
 	return func(c *Client) error {
+		if c.restored {
+			return nil
+		}
 		var res AuthLoginResponse
+		l.Session = c.loginSession(l.Session)
 		l.Format = "sid"
 		if err := c.Call(context.Background(), l, &res); err != nil {
 			return err
 		}
-		c.sid = res.SID
+		return c.storeLogin(l.Session, true, &res)
+	}
+}
+
+// ClientCookieJar configures a cookie jar for the Client. Cookies set by
+// responses are stored in it, and sent along with subsequent requests.
+func ClientCookieJar(jar http.CookieJar) ClientOption {
+	return func(c *Client) error {
+		c.jar = jar
+		return nil
+	}
+}
+
+// ClientCookieLogin configures the Client with a cookie based session from the
+// given credentials, instead of a "sid". The session cookie is stored in the
+// cookie jar, which is created if one was not configured with
+// ClientCookieJar. Like ClientLogin, it should typically be specified after
+// all the other options.
+func ClientCookieLogin(l AuthLogin) ClientOption {
+	return func(c *Client) error {
+		if c.jar == nil {
+			jar, err := cookiejar.New(nil)
+			if err != nil {
+				return err
+			}
+			c.jar = jar
+		}
+		l.Session = c.loginSession(l.Session)
+		l.Format = "cookie"
+		return c.Call(context.Background(), l, nil)
+	}
+}
+
+// ClientSession configures the default application session name, such as
+// SessionDownloadStation. It is used when logging in with credentials that do
+// not specify a Session, and when logging out the default "sid". It should be
+// specified before any of the login options.
+func ClientSession(name string) ClientOption {
+	return func(c *Client) error {
+		c.session = name
+		return nil
+	}
+}
+
+// ClientCredentials configures the credentials used to log into application
+// sessions on demand. Requests with a Session the Client does not yet hold a
+// "sid" for will trigger a login into that session.
+func ClientCredentials(l AuthLogin) ClientOption {
+	return func(c *Client) error {
+		c.credentials = &l
+		return nil
+	}
+}
+
+// ClientAutoRelogin configures the Client to log in again using the given
+// credentials when a request fails because the session timed out or was
+// interrupted by a duplicate login, and then retry the request once. The
+// credentials are also used as with ClientCredentials.
+func ClientAutoRelogin(l AuthLogin) ClientOption {
+	return func(c *Client) error {
+		c.credentials = &l
+		c.autoRelogin = true
 		return nil
 	}
 }
 
 // NewClient creates a new client with the given options.
 func NewClient(options ...ClientOption) (*Client, error) {
-	c := Client{transport: http.DefaultTransport}
+	c := Client{
+		clientOptions: clientOptions{
+			transport: http.DefaultTransport,
+			apiInfo:   &apiInfoCache{},
+		},
+	}
 	for _, o := range options {
 		if err := o(&c); err != nil {
 			return nil, err
@@ -217,6 +1035,9 @@ func NewClient(options ...ClientOption) (*Client, error) {
 	if c.url == nil || !c.url.IsAbs() {
 		return nil, errURLMisconfigured
 	}
+	if c.keepAlive > 0 {
+		c.startKeepAlive(c.keepAlive)
+	}
 	return &c, nil
 }
 
@@ -226,36 +1047,114 @@ const (
 	authLoginVersion = "3"
 )
 
+// Errors returned by SYNO.API.Auth. Other APIs use the same codes with
+// different meanings.
+const (
+	ErrorAuthNoSuchAccount           = Error(400)
+	ErrorAuthAccountDisabled         = Error(401)
+	ErrorAuthPermissionDenied        = Error(402)
+	ErrorAuthOTPRequired             = Error(403)
+	ErrorAuthOTPFailed               = Error(404)
+	ErrorAuthOTPEnforced             = Error(406)
+	ErrorAuthBlockedIP               = Error(407)
+	ErrorAuthExpiredPasswordNoChange = Error(408)
+	ErrorAuthExpiredPassword         = Error(409)
+	ErrorAuthPasswordMustChange      = Error(410)
+)
+
+func init() {
+	RegisterAPIErrorStrings(authLoginAPI, map[Error]string{
+		ErrorAuthNoSuchAccount:           "no such account or incorrect password",
+		ErrorAuthAccountDisabled:         "account disabled",
+		ErrorAuthPermissionDenied:        "permission denied",
+		ErrorAuthOTPRequired:             "2-step verification code required",
+		ErrorAuthOTPFailed:               "failed to authenticate 2-step verification code",
+		ErrorAuthOTPEnforced:             "2-step verification enforced",
+		ErrorAuthBlockedIP:               "blocked IP source",
+		ErrorAuthExpiredPasswordNoChange: "expired password cannot be changed",
+		ErrorAuthExpiredPassword:         "expired password",
+		ErrorAuthPasswordMustChange:      "password must be changed",
+	})
+}
+
+// IsOTPRequired reports if a login failed because the account requires a
+// 2-step verification code, in which case it should be retried with the
+// AuthLogin.OTPCode set.
+func IsOTPRequired(err error) bool {
+	var code Error
+	return errors.As(err, &code) && code == ErrorAuthOTPRequired
+}
+
 // AuthLogin logs in an account. The response is AuthLoginResponse.
 type AuthLogin struct {
-	Account  string
-	Password string
-	Session  string
-	Format   string
-	OTPCode  string
+	Account  string `syno:"account,omitempty"`
+	Password string `syno:"passwd,omitempty"`
+	Session  string `syno:"session,omitempty"`
+	Format   string `syno:"format,omitempty"`
+	OTPCode  string `syno:"otp_code,omitempty"`
+
+	// EnableSynoToken requests a SynoToken, which DSM 7 requires alongside the
+	// "sid" when protection against cross-site request forgery is enabled. The
+	// Client attaches it to subsequent requests made with the "sid".
+	EnableSynoToken bool `syno:"enable_syno_token,omitempty,yesno"`
+
+	// EnableDeviceToken requests the device be remembered when logging in with
+	// an OTPCode. The returned AuthLoginResponse.DeviceID can then be given as
+	// DeviceID along with the same DeviceName to skip the OTP on later logins.
+	EnableDeviceToken bool   `syno:"enable_device_token,omitempty,yesno"`
+	DeviceName        string `syno:"device_name,omitempty"`
+	DeviceID          string `syno:"device_id,omitempty"`
 }
 
 // MarshalRequest serializes the instance to a Request.
 func (a AuthLogin) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(a)
+	if err != nil {
+		return nil, err
+	}
 	return &Request{
 		Path:    authLoginPath,
 		API:     authLoginAPI,
 		Version: authLoginVersion,
 		Method:  "login",
-		Params: dropEmpty(url.Values{
-			"account":  []string{a.Account},
-			"passwd":   []string{a.Password},
-			"session":  []string{a.Session},
-			"format":   []string{a.Format},
-			"otp_code": []string{a.OTPCode},
-		}),
+		Params:  p,
 	}, nil
 }
 
+// Validate checks that EnableDeviceToken is accompanied by an OTPCode.
+func (a AuthLogin) Validate() error {
+	return requireWith("EnableDeviceToken", a.EnableDeviceToken, "OTPCode", a.OTPCode)
+}
+
 // AuthLoginResponse is the response from an AuthLogin request.
 type AuthLoginResponse struct {
-	SID    string
-	Cookie string
+	SID       string
+	Cookie    string
+	SynoToken string
+	DeviceID  string `json:"did"`
+}
+
+// AuthLogout logs out the session identified by the "sid" the request is made
+// with. It does not have a response.
+type AuthLogout struct {
+	Session string `syno:"session,omitempty"`
+	SID     string
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (a AuthLogout) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(a)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		Path:    authLoginPath,
+		API:     authLoginAPI,
+		Version: authLoginVersion,
+		Method:  "logout",
+		Params:  p,
+		SID:     a.SID,
+	}, nil
 }
 
 const (
@@ -264,57 +1163,358 @@ const (
 	downloadTaskVersion = "1"
 )
 
-// DownloadTaskList perfoms a list call for download tasks.
+// Errors returned by the SYNO.DownloadStation APIs. Other APIs use the same
+// codes with different meanings.
+const (
+	ErrorDownloadFileUploadFailed     = Error(400)
+	ErrorDownloadMaxTasksReached      = Error(401)
+	ErrorDownloadDestinationDenied    = Error(402)
+	ErrorDownloadDestinationNotExist  = Error(403)
+	ErrorDownloadInvalidTaskID        = Error(404)
+	ErrorDownloadInvalidTaskAction    = Error(405)
+	ErrorDownloadNoDefaultDestination = Error(406)
+	ErrorDownloadSetDestinationFailed = Error(407)
+	ErrorDownloadFileNotExist         = Error(408)
+)
+
+func init() {
+	RegisterAPIErrorStrings("SYNO.DownloadStation", map[Error]string{
+		ErrorDownloadFileUploadFailed:     "file upload failed",
+		ErrorDownloadMaxTasksReached:      "max number of tasks reached",
+		ErrorDownloadDestinationDenied:    "destination denied",
+		ErrorDownloadDestinationNotExist:  "destination does not exist",
+		ErrorDownloadInvalidTaskID:        "invalid task id",
+		ErrorDownloadInvalidTaskAction:    "invalid task action",
+		ErrorDownloadNoDefaultDestination: "no default destination",
+		ErrorDownloadSetDestinationFailed: "set destination failed",
+		ErrorDownloadFileNotExist:         "file does not exist",
+	})
+}
+
+// DownloadTaskList perfoms a list call for download tasks. The response is
+// DownloadTaskListResponse.
 type DownloadTaskList struct {
-	Offset     int
-	Limit      int
-	Additional []string
+	Offset     int      `syno:"offset,omitempty"`
+	Limit      int      `syno:"limit,omitempty"`
+	Additional []string `syno:"additional,omitempty"`
+
+	// SortBy, SortDirection, Status and Type sort and filter the tasks on the
+	// server. SYNO.DownloadStation.Task does not support them, so they are
+	// only used when the request is made as DownloadTask2List by
+	// CallDownloadTask, and are ignored otherwise.
+	SortBy        DownloadTaskSortBy   `syno:"-"`
+	SortDirection SortDirection        `syno:"-"`
+	Status        []DownloadTaskStatus `syno:"-"`
+	Type          []DownloadTaskType   `syno:"-"`
 }
 
 // MarshalRequest serializes the instance to a Request.
 func (d DownloadTaskList) MarshalRequest() (*Request, error) {
-	v := url.Values{}
-	if d.Offset != 0 {
-		v.Add("offset", strconv.Itoa(d.Offset))
-	}
-	if d.Limit != 0 {
-		v.Add("limit", strconv.Itoa(d.Limit))
-	}
-	if len(d.Additional) > 0 {
-		v.Add("additional", strings.Join(d.Additional, ","))
+	p, err := MarshalParams(d)
+	if err != nil {
+		return nil, err
 	}
-
 	return &Request{
 		Path:    downloadTaskPath,
 		API:     downloadTaskAPI,
 		Version: downloadTaskVersion,
 		Method:  "list",
-		Params:  v,
+		Params:  p,
+		Session: SessionDownloadStation,
 	}, nil
 }
 
-// DownloadTaskCreate creates a new download task. It does not have a response.
-type DownloadTaskCreate struct {
-	URI           string
-	Username      string
-	Password      string
-	UnzipPassword string
-	Destination   string
+// DownloadTaskGetInfo gets the download tasks with the given IDs. The
+// response is DownloadTaskGetInfoResponse.
+type DownloadTaskGetInfo struct {
+	IDs        []string `syno:"id"`
+	Additional []string `syno:"additional,omitempty"`
+}
+
+// Validate checks that at least one task ID is given.
+func (d DownloadTaskGetInfo) Validate() error {
+	return requireField("IDs", strings.Join(d.IDs, ","))
 }
 
 // MarshalRequest serializes the instance to a Request.
-func (d DownloadTaskCreate) MarshalRequest() (*Request, error) {
+func (d DownloadTaskGetInfo) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(d)
+	if err != nil {
+		return nil, err
+	}
 	return &Request{
 		Path:    downloadTaskPath,
 		API:     downloadTaskAPI,
 		Version: downloadTaskVersion,
-		Method:  "create",
-		Params: dropEmpty(url.Values{
-			"uri":            []string{d.URI},
-			"username":       []string{d.Username},
-			"password":       []string{d.Password},
-			"unzip_password": []string{d.UnzipPassword},
-			"destination":    []string{d.Destination},
-		}),
+		Method:  "getinfo",
+		Params:  p,
+		Session: SessionDownloadStation,
+	}, nil
+}
+
+// DownloadTaskGetInfoResponse is the response from a DownloadTaskGetInfo
+// request.
+type DownloadTaskGetInfoResponse struct {
+	Tasks []DownloadTask `json:"tasks"`
+}
+
+// DownloadTask is a download task as returned by DownloadTaskList and
+// DownloadTaskGetInfo. The Additional details are only included if requested.
+type DownloadTask struct {
+	ID         string                  `json:"id"`
+	Type       DownloadTaskType        `json:"type"`
+	Username   string                  `json:"username"`
+	Title      string                  `json:"title"`
+	Size       Bytes                   `json:"size"`
+	Status     DownloadTaskStatus      `json:"status"`
+	Additional *DownloadTaskAdditional `json:"additional,omitempty"`
+}
+
+// Additional information that can be requested with
+// DownloadTaskList.Additional.
+const (
+	DownloadTaskAdditionalDetail   = "detail"
+	DownloadTaskAdditionalTransfer = "transfer"
+	DownloadTaskAdditionalFile     = "file"
+	DownloadTaskAdditionalTracker  = "tracker"
+	DownloadTaskAdditionalPeer     = "peer"
+)
+
+// DownloadTaskAdditional holds the details requested with
+// DownloadTaskList.Additional.
+type DownloadTaskAdditional struct {
+	Detail   *DownloadTaskDetail   `json:"detail,omitempty"`
+	Transfer *DownloadTaskTransfer `json:"transfer,omitempty"`
+	File     []DownloadTaskFile    `json:"file,omitempty"`
+	Tracker  []DownloadTaskTracker `json:"tracker,omitempty"`
+	Peer     []DownloadTaskPeer    `json:"peer,omitempty"`
+}
+
+// DownloadTaskDetail is the "detail" additional information of a task.
+type DownloadTaskDetail struct {
+	Destination       string `json:"destination"`
+	URI               string `json:"uri"`
+	Priority          string `json:"priority"`
+	TotalPeers        int    `json:"total_peers"`
+	ConnectedSeeders  int    `json:"connected_seeders"`
+	ConnectedLeechers int    `json:"connected_leechers"`
+	CreateTime        Time   `json:"create_time"`
+	StartedTime       Time   `json:"started_time"`
+	CompletedTime     Time   `json:"completed_time"`
+}
+
+// DownloadTaskTransfer is the "transfer" additional information of a task.
+type DownloadTaskTransfer struct {
+	SizeDownloaded Bytes `json:"size_downloaded"`
+	SizeUploaded   Bytes `json:"size_uploaded"`
+
+	// SpeedDownload and SpeedUpload are in bytes per second.
+	SpeedDownload Bytes `json:"speed_download"`
+	SpeedUpload   Bytes `json:"speed_upload"`
+}
+
+// DownloadTaskFile is a file of a task, part of the "file" additional
+// information. Priority is one of "skip", "low", "normal" or "high".
+type DownloadTaskFile struct {
+	Filename       string `json:"filename"`
+	Size           Bytes  `json:"size"`
+	SizeDownloaded Bytes  `json:"size_downloaded"`
+	Priority       string `json:"priority"`
+}
+
+// DownloadTaskTracker is a BitTorrent tracker of a task, part of the
+// "tracker" additional information.
+type DownloadTaskTracker struct {
+	URL    string `json:"url"`
+	Status string `json:"status"`
+	Seeds  int    `json:"seeds"`
+	Peers  int    `json:"peers"`
+
+	// UpdateTimer is the number of seconds until the tracker is next updated.
+	UpdateTimer int `json:"update_timer"`
+}
+
+// DownloadTaskPeer is a BitTorrent peer of a task, part of the "peer"
+// additional information.
+type DownloadTaskPeer struct {
+	Address string `json:"address"`
+	Agent   string `json:"agent"`
+
+	// Progress is the fraction of the task the peer has, from 0 to 1.
+	Progress float64 `json:"progress"`
+
+	// SpeedDownload and SpeedUpload are in bytes per second.
+	SpeedDownload Bytes `json:"speed_download"`
+	SpeedUpload   Bytes `json:"speed_upload"`
+}
+
+// DownloadTaskCreate creates a new download task. It does not have a response.
+type DownloadTaskCreate struct {
+	URI           string `syno:"uri,omitempty"`
+	Username      string `syno:"username,omitempty"`
+	Password      string `syno:"password,omitempty"`
+	UnzipPassword string `syno:"unzip_password,omitempty"`
+	Destination   string `syno:"destination,omitempty"`
+
+	// File is a torrent or NZB file to create the task from instead of a URI.
+	File io.Reader
+	// FileName is the name the File is uploaded with.
+	FileName string
+}
+
+// Validate checks that exactly one of URI and File is set.
+func (d DownloadTaskCreate) Validate() error {
+	if d.File != nil {
+		if d.URI != "" {
+			return &ValidationError{Field: "File", Reason: "conflicts with URI"}
+		}
+		return nil
+	}
+	return requireField("URI", d.URI)
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTaskCreate) MarshalRequest() (*Request, error) {
+	p, err := MarshalParams(d)
+	if err != nil {
+		return nil, err
+	}
+	r := &Request{
+		Path:       downloadTaskPath,
+		API:        downloadTaskAPI,
+		Version:    downloadTaskVersion,
+		Method:     "create",
+		HTTPMethod: http.MethodPost,
+		Params:     p,
+		Session:    SessionDownloadStation,
+	}
+	if d.File != nil {
+		r.Files = []RequestFile{{Name: "file", Filename: d.FileName, Body: d.File}}
+	}
+	return r, nil
+}
+
+// DownloadTaskResult is the outcome of an action on a single download task.
+// Error is zero if the action succeeded.
+type DownloadTaskResult struct {
+	ID    string `json:"id"`
+	Error Error  `json:"error"`
+}
+
+// Err returns the Error for the task, or nil if the action succeeded.
+func (r DownloadTaskResult) Err() error {
+	if r.Error == 0 {
+		return nil
+	}
+	return r.Error
+}
+
+// DownloadTaskResults is the response for actions on multiple download tasks,
+// with one result per task ID.
+type DownloadTaskResults []DownloadTaskResult
+
+// Failed returns the results of the tasks the action failed for.
+func (r DownloadTaskResults) Failed() DownloadTaskResults {
+	var failed DownloadTaskResults
+	for _, res := range r {
+		if res.Error != 0 {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// DownloadTaskDelete deletes download tasks. The response is
+// DownloadTaskResults. ForceComplete moves the files of unfinished tasks to
+// the destination instead of removing them.
+type DownloadTaskDelete struct {
+	IDs           []string `syno:"id"`
+	ForceComplete bool     `syno:"force_complete,omitempty"`
+}
+
+// Validate checks that at least one task ID is given.
+func (d DownloadTaskDelete) Validate() error {
+	return requireField("IDs", strings.Join(d.IDs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTaskDelete) MarshalRequest() (*Request, error) {
+	return downloadTaskAction("delete", d)
+}
+
+// DownloadTaskPause pauses download tasks. The response is
+// DownloadTaskResults.
+type DownloadTaskPause struct {
+	IDs []string `syno:"id"`
+}
+
+// Validate checks that at least one task ID is given.
+func (d DownloadTaskPause) Validate() error {
+	return requireField("IDs", strings.Join(d.IDs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTaskPause) MarshalRequest() (*Request, error) {
+	return downloadTaskAction("pause", d)
+}
+
+// DownloadTaskResume resumes paused download tasks. The response is
+// DownloadTaskResults.
+type DownloadTaskResume struct {
+	IDs []string `syno:"id"`
+}
+
+// Validate checks that at least one task ID is given.
+func (d DownloadTaskResume) Validate() error {
+	return requireField("IDs", strings.Join(d.IDs, ","))
+}
+
+// MarshalRequest serializes the instance to a Request.
+func (d DownloadTaskResume) MarshalRequest() (*Request, error) {
+	return downloadTaskAction("resume", d)
+}
+
+// DownloadTaskEdit changes the destination of download tasks, which must be
+// an existing shared folder path without the leading slash, such as
+// "video/movies". The response is DownloadTaskResults.
+type DownloadTaskEdit struct {
+	IDs         []string `syno:"id"`
+	Destination string   `syno:"destination"`
+}
+
+// Validate checks that task IDs and the destination are given.
+func (d DownloadTaskEdit) Validate() error {
+	if err := requireField("IDs", strings.Join(d.IDs, ",")); err != nil {
+		return err
+	}
+	return requireField("Destination", d.Destination)
+}
+
+// MarshalRequest serializes the instance to a Request. The edit method was
+// added in version 2 of the API.
+func (d DownloadTaskEdit) MarshalRequest() (*Request, error) {
+	r, err := downloadTaskAction("edit", d)
+	if err != nil {
+		return nil, err
+	}
+	r.Version = "2"
+	return r, nil
+}
+
+// downloadTaskAction builds the Request for a method acting on existing
+// download tasks.
+func downloadTaskAction(method string, v interface{}) (*Request, error) {
+	p, err := MarshalParams(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Request{
+		Path:       downloadTaskPath,
+		API:        downloadTaskAPI,
+		Version:    downloadTaskVersion,
+		Method:     method,
+		HTTPMethod: http.MethodPost,
+		Params:     p,
+		Session:    SessionDownloadStation,
 	}, nil
 }
@@ -2,13 +2,18 @@ package syno
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/facebookgo/ensure"
 	"github.com/facebookgo/jsonpipe"
@@ -22,19 +27,51 @@ func TestGenericErrorString(t *testing.T) {
 	ensure.DeepEqual(t, Error(42).Error(), "syno: error code 42")
 }
 
-func TestDropEmpty(t *testing.T) {
+func TestRegisterErrorStrings(t *testing.T) {
+	RegisterErrorStrings(map[Error]string{Error(9001): "custom"})
+	ensure.DeepEqual(t, Error(9001).Error(), "syno: custom (9001)")
+}
+
+func TestRegisterErrorStringsOverride(t *testing.T) {
+	defer RegisterErrorStrings(map[Error]string{ErrorInvalidAPI: "invalid API"})
+	RegisterErrorStrings(map[Error]string{ErrorInvalidAPI: "API invalide"})
+	ensure.DeepEqual(t, ErrorInvalidAPI.Error(), "syno: API invalide (102)")
+}
+
+func TestRegisterAPIErrorStrings(t *testing.T) {
+	RegisterAPIErrorStrings("SYNO.Test", map[Error]string{Error(9002): "scoped"})
+	ensure.DeepEqual(t, ErrorString("SYNO.Test", Error(9002)), "syno: scoped (9002)")
+	ensure.DeepEqual(t, ErrorString("SYNO.Test.Sub", Error(9002)), "syno: scoped (9002)")
+	ensure.DeepEqual(t, ErrorString("SYNO.Other", Error(9002)), "syno: error code 9002")
+	ensure.DeepEqual(t, Error(9002).Error(), "syno: error code 9002")
+}
+
+func TestErrorStringFallsBackToGlobal(t *testing.T) {
 	ensure.DeepEqual(
 		t,
-		dropEmpty(url.Values{
-			"keep": []string{"foo"},
-			"drop": []string{""},
-		}),
-		url.Values{
-			"keep": []string{"foo"},
-		},
+		ErrorString("SYNO.Test", ErrorUnknown),
+		"syno: unknown API error (100)",
 	)
 }
 
+func TestLookupErrorString(t *testing.T) {
+	RegisterAPIErrorStrings("SYNO.Lookup", map[Error]string{Error(9003): "found"})
+	s, ok := LookupErrorString("SYNO.Lookup.Sub", Error(9003))
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, s, "found")
+	_, ok = LookupErrorString("SYNO.Lookup", Error(9004))
+	ensure.False(t, ok)
+	s, ok = LookupErrorString("", ErrorInvalidMethod)
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, s, "invalid method")
+}
+
+func TestAPIErrorUsesRegisteredStrings(t *testing.T) {
+	RegisterAPIErrorStrings("SYNO.Ext", map[Error]string{Error(501): "extension"})
+	err := wrapAPIError(&Request{API: "SYNO.Ext.Thing"}, Error(501))
+	ensure.DeepEqual(t, err.Error(), "syno: SYNO.Ext.Thing: extension (501)")
+}
+
 type transportFunc func(*http.Request) (*http.Response, error)
 
 func (f transportFunc) RoundTrip(r *http.Request) (*http.Response, error) {
@@ -78,6 +115,123 @@ func TestClientDoSuccess(t *testing.T) {
 	ensure.DeepEqual(t, res, data)
 }
 
+func TestEncodeQuery(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		encodeQuery(&Request{
+			API:     "a",
+			Version: "1",
+			Method:  "m",
+			Params: url.Values{
+				"z": []string{"1", "2"},
+				"b": []string{"x y"},
+			},
+		}, "s&id", ""),
+		"api=a&version=1&method=m&_sid=s%26id&b=x+y&z=1&z=2",
+	)
+}
+
+func TestEncodeQueryNoSID(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		encodeQuery(&Request{API: "a", Version: "1", Method: "m"}, "", ""),
+		"api=a&version=1&method=m",
+	)
+}
+
+func BenchmarkEncodeQuery(b *testing.B) {
+	r := &Request{
+		API:     downloadTaskAPI,
+		Version: downloadTaskVersion,
+		Method:  "list",
+		Params: url.Values{
+			"offset":     []string{"100"},
+			"limit":      []string{"50"},
+			"additional": []string{"detail,transfer"},
+		},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encodeQuery(r, "sid", "")
+	}
+}
+
+func BenchmarkClientDo(b *testing.B) {
+	const body = `{"success":true,"data":{}}`
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("sid"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		})),
+	)
+	ensure.Nil(b, err)
+	r := &Request{
+		Path:    downloadTaskPath,
+		API:     downloadTaskAPI,
+		Version: downloadTaskVersion,
+		Method:  "list",
+		Params: url.Values{
+			"offset": []string{"100"},
+			"limit":  []string{"50"},
+		},
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := c.Do(ctx, r, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeQuerySynoToken(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		encodeQuery(&Request{API: "a", Version: "1", Method: "m"}, "sid", "tok"),
+		"api=a&version=1&method=m&_sid=sid&SynoToken=tok",
+	)
+}
+
+func TestClientLoginSynoToken(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			if v.Get("api") == authLoginAPI {
+				ensure.DeepEqual(t, v["enable_syno_token"], []string{"yes"})
+				return &http.Response{
+					Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+						"success": true,
+						"data": map[string]string{
+							"sid":       "sid",
+							"synotoken": "token",
+						},
+					})),
+				}, nil
+			}
+			ensure.DeepEqual(t, v["SynoToken"], []string{"token"})
+			ensure.DeepEqual(t, r.Header.Get("X-SYNO-TOKEN"), "token")
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+		ClientLogin(AuthLogin{
+			Account:         "account",
+			Password:        "password",
+			EnableSynoToken: true,
+		}),
+	)
+	ensure.Nil(t, err)
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+}
+
 func TestClientDoRequestSID(t *testing.T) {
 	const reqSID = "reqSID"
 	c, err := NewClient(
@@ -99,132 +253,751 @@ func TestClientDoRequestSID(t *testing.T) {
 	ensure.Nil(t, err)
 }
 
-func TestClientDoClientSID(t *testing.T) {
-	const clientSID = "reqSID"
+func TestClientDoClientSID(t *testing.T) {
+	const clientSID = "reqSID"
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID(clientSID),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v["_sid"], []string{clientSID})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestClientDoTransportError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, givenErr
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientDoEntryPath(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/nas/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/webapi/entry.cgi")
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{API: "SYNO.Core.System"}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestClientDoPath(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, authLoginPath)
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{Path: authLoginPath}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestClientDoPost(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("sid"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.RawQuery, "")
+			ensure.DeepEqual(
+				t,
+				r.Header.Get("Content-Type"),
+				"application/x-www-form-urlencoded",
+			)
+			ensure.Nil(t, r.ParseForm())
+			ensure.DeepEqual(t, r.PostForm, url.Values{
+				"api":     []string{"api"},
+				"method":  []string{"method"},
+				"version": []string{"version"},
+				"_sid":    []string{"sid"},
+				"foo":     []string{"foo"},
+			})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{
+		API:        "api",
+		Method:     "method",
+		Version:    "version",
+		HTTPMethod: "POST",
+		Params: url.Values{
+			"foo": []string{"foo"},
+		},
+	}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestClientDoLongURLSwitchesToPost(t *testing.T) {
+	long := strings.Repeat("x", maxGetURLLength)
+	var methods []string
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			methods = append(methods, r.Method)
+			ensure.Nil(t, r.ParseForm())
+			ensure.DeepEqual(t, r.Form.Get("api"), "api")
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ctx := context.Background()
+	ensure.Nil(t, c.Do(ctx, &Request{API: "api"}, nil))
+	ensure.Nil(t, c.Do(ctx, &Request{
+		API:    "api",
+		Params: url.Values{"foo": []string{long}},
+	}, nil))
+	ensure.DeepEqual(t, methods, []string{"GET", "POST"})
+}
+
+func TestClientDoUnsupportedHTTPMethod(t *testing.T) {
+	c, err := NewClient(ClientRawURL("http://foo.com/"))
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{HTTPMethod: "PUT"}, nil)
+	ensure.Err(t, err, regexp.MustCompile(`unsupported HTTP method "PUT"`))
+}
+
+func TestClientDoContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx, cancel := context.WithCancel(
+		context.WithValue(context.Background(), ctxKey{}, "value"))
+	cancel()
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Context().Value(ctxKey{}), "value")
+			return nil, r.Context().Err()
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(ctx, &Request{}, nil)
+	ensure.DeepEqual(t, err, context.Canceled)
+}
+
+func TestClientTimeout(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTimeout(time.Hour),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			deadline, ok := r.Context().Deadline()
+			ensure.True(t, ok)
+			ensure.True(t, time.Until(deadline) > 59*time.Minute)
+			return nil, errors.New("")
+		})),
+	)
+	ensure.Nil(t, err)
+	c.Do(context.Background(), &Request{}, nil)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTimeout(time.Hour),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{Timeout: time.Millisecond}, nil)
+	ensure.DeepEqual(t, err, context.DeadlineExceeded)
+}
+
+func TestClientNoTimeout(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			_, ok := r.Context().Deadline()
+			ensure.False(t, ok)
+			return nil, errors.New("")
+		})),
+	)
+	ensure.Nil(t, err)
+	c.Do(context.Background(), &Request{}, nil)
+}
+
+func TestClientDoNonJSON(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(".")),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	ensure.Err(t, err, regexp.MustCompile("invalid character"))
+}
+
+func TestClientDoDataJSONError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	var res string
+	err = c.Do(context.Background(), &Request{}, &res)
+	ensure.Err(t, err, regexp.MustCompile("cannot unmarshal bool into Go value of type string"))
+}
+
+func TestClientAPIError(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"error": map[string]interface{}{
+						"code": ErrorUnknown,
+					},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	ensure.True(t, errors.Is(err, ErrorUnknown))
+}
+
+func TestClientLogin(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			ensure.Subset(t, v, url.Values{
+				"account": []string{"account"},
+				"passwd":  []string{"password"},
+			})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data": map[string]string{
+						"sid": "sid",
+					},
+				})),
+			}, nil
+		})),
+		ClientLogin(AuthLogin{
+			Account:  "account",
+			Password: "password",
+		}),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.sid, "sid")
+}
+
+func TestAuthLoginResponseDeviceID(t *testing.T) {
+	var res AuthLoginResponse
+	err := json.Unmarshal([]byte(`{"sid":"s","did":"d"}`), &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, AuthLoginResponse{SID: "s", DeviceID: "d"})
+}
+
+func TestClientLoginError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, givenErr
+		})),
+		ClientLogin(AuthLogin{
+			Account:  "account",
+			Password: "password",
+		}),
+	)
+	ensure.True(t, c == nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientLoginNamedSession(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data": map[string]string{
+						"sid": "sid",
+					},
+				})),
+			}, nil
+		})),
+		ClientLogin(AuthLogin{Session: SessionFileStation}),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.sid, "sid")
+	ensure.DeepEqual(t, c.sessions, map[string]string{SessionFileStation: "sid"})
+}
+
+func TestClientSessionLoginOnDemand(t *testing.T) {
+	var logins int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("default"),
+		ClientCredentials(AuthLogin{Account: "a", Password: "b"}),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			if v.Get("api") == authLoginAPI {
+				logins++
+				ensure.Subset(t, v, url.Values{
+					"account": []string{"a"},
+					"session": []string{SessionDownloadStation},
+					"format":  []string{"sid"},
+				})
+				return &http.Response{
+					Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+						"success": true,
+						"data":    map[string]string{"sid": "ds"},
+					})),
+				}, nil
+			}
+			ensure.DeepEqual(t, v["_sid"], []string{"ds"})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		err = c.Do(context.Background(), &Request{Session: SessionDownloadStation}, nil)
+		ensure.Nil(t, err)
+	}
+	ensure.DeepEqual(t, logins, 1)
+}
+
+func TestClientSessionWithoutCredentials(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientSID("default"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v["_sid"], []string{"default"})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{Session: SessionFileStation}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestClientSessionLoginError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientCredentials(AuthLogin{Account: "a"}),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, givenErr
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{Session: SessionFileStation}, nil)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestLoginSessionWithoutCredentials(t *testing.T) {
+	c, err := NewClient(ClientRawURL("http://foo.com/"))
+	ensure.Nil(t, err)
+	_, err = c.LoginSession(context.Background(), SessionFileStation)
+	ensure.DeepEqual(t, err, errNoCredentials)
+}
+
+// sessionTransport simulates an API which accepts a single valid "sid" and
+// hands out a new one on every login.
+type sessionTransport struct {
+	t      testing.TB
+	valid  string
+	logins int
+	calls  int
+}
+
+func (s *sessionTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	v, err := url.ParseQuery(r.URL.RawQuery)
+	ensure.Nil(s.t, err)
+	res := map[string]interface{}{"success": true}
+	if v.Get("api") == authLoginAPI {
+		s.logins++
+		s.valid = fmt.Sprint("sid", s.logins)
+		res["data"] = map[string]string{"sid": s.valid}
+	} else {
+		s.calls++
+		if v.Get("_sid") != s.valid {
+			res = map[string]interface{}{
+				"error": map[string]interface{}{"code": ErrorSessionTimeout},
+			}
+		}
+	}
+	return &http.Response{Body: ioutil.NopCloser(jsonpipe.Encode(res))}, nil
+}
+
+func TestClientAutoRelogin(t *testing.T) {
+	st := &sessionTransport{t: t}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(st),
+		ClientSID("expired"),
+		ClientAutoRelogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+	ensure.DeepEqual(t, st.logins, 1)
+	ensure.DeepEqual(t, st.calls, 2)
+	ensure.DeepEqual(t, c.sid, "sid1")
+}
+
+func TestClientAutoReloginNamedSession(t *testing.T) {
+	st := &sessionTransport{t: t}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(st),
+		ClientAutoRelogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	r := &Request{Session: SessionDownloadStation}
+	ensure.Nil(t, c.Do(context.Background(), r, nil))
+	ensure.DeepEqual(t, st.logins, 1)
+	st.valid = "other"
+	ensure.Nil(t, c.Do(context.Background(), r, nil))
+	ensure.DeepEqual(t, st.logins, 2)
+	ensure.DeepEqual(t, c.sessions[SessionDownloadStation], "sid2")
+	ensure.DeepEqual(t, c.sid, "")
+}
+
+func TestClientAutoReloginRequestSID(t *testing.T) {
+	st := &sessionTransport{t: t}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(st),
+		ClientAutoRelogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{SID: "explicit"}, nil)
+	ensure.True(t, errors.Is(err, ErrorSessionTimeout))
+	ensure.DeepEqual(t, st.logins, 0)
+}
+
+func TestClientAutoReloginError(t *testing.T) {
+	var calls int
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls > 1 {
+				return nil, givenErr
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"error": map[string]interface{}{
+						"code": ErrorSessionInterruptedDuplicateLogin,
+					},
+				})),
+			}, nil
+		})),
+		ClientAutoRelogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	ensure.DeepEqual(t, err, givenErr)
+	ensure.DeepEqual(t, calls, 2)
+}
+
+func TestClientWithoutAutoRelogin(t *testing.T) {
+	st := &sessionTransport{t: t}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(st),
+		ClientSID("expired"),
+		ClientCredentials(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	ensure.True(t, errors.Is(err, ErrorSessionTimeout))
+	ensure.DeepEqual(t, st.logins, 0)
+}
+
+func TestClientLoginWithoutCredentials(t *testing.T) {
+	c, err := NewClient(ClientRawURL("http://foo.com/"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Login(context.Background()), errNoCredentials)
+}
+
+func TestClientClose(t *testing.T) {
+	var logouts []url.Values
 	c, err := NewClient(
 		ClientRawURL("http://foo.com/"),
-		ClientSID(clientSID),
 		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
 			v, err := url.ParseQuery(r.URL.RawQuery)
 			ensure.Nil(t, err)
-			ensure.DeepEqual(t, v["_sid"], []string{clientSID})
+			ensure.DeepEqual(t, v.Get("method"), "logout")
+			logouts = append(logouts, v)
 			return &http.Response{
 				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
 					"success": true,
 				})),
 			}, nil
 		})),
+		ClientSID("default"),
 	)
 	ensure.Nil(t, err)
-	err = c.Do(context.Background(), &Request{}, nil)
-	ensure.Nil(t, err)
+	c.storeLogin(SessionDownloadStation, false, &AuthLoginResponse{SID: "ds"})
+	c.storeLogin(SessionFileStation, false, &AuthLoginResponse{SID: "default"})
+	ensure.Nil(t, c.Close(context.Background()))
+	ensure.DeepEqual(t, len(logouts), 2)
+	sids := map[string]string{}
+	for _, v := range logouts {
+		sids[v.Get("_sid")] = v.Get("session")
+	}
+	ensure.DeepEqual(t, sids["ds"], SessionDownloadStation)
+	_, ok := sids["default"]
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, c.sid, "")
+	ensure.True(t, c.sessions == nil)
 }
 
-func TestClientDoTransportError(t *testing.T) {
+func TestClientCloseError(t *testing.T) {
 	givenErr := errors.New("")
 	c, err := NewClient(
 		ClientRawURL("http://foo.com/"),
 		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
 			return nil, givenErr
 		})),
+		ClientSID("default"),
 	)
 	ensure.Nil(t, err)
-	err = c.Do(context.Background(), &Request{}, nil)
-	ensure.DeepEqual(t, err, givenErr)
+	ensure.DeepEqual(t, c.Close(context.Background()), givenErr)
+	ensure.DeepEqual(t, c.sid, "")
 }
 
-func TestClientDoNonJSON(t *testing.T) {
+func TestClientCloseEmpty(t *testing.T) {
+	c, err := NewClient(ClientRawURL("http://foo.com/"))
+	ensure.Nil(t, err)
+	ensure.Nil(t, c.Close(context.Background()))
+}
+
+func TestClientCookieLogin(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			res := &http.Response{
+				Header: make(http.Header),
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
+			}
+			if v.Get("api") == authLoginAPI {
+				ensure.DeepEqual(t, v["format"], []string{"cookie"})
+				res.Header.Set("Set-Cookie", "id=cookie-sid; path=/")
+				return res, nil
+			}
+			ensure.DeepEqual(t, v["_sid"], []string(nil))
+			cookie, err := r.Cookie("id")
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, cookie.Value, "cookie-sid")
+			return res, nil
+		})),
+		ClientCookieLogin(AuthLogin{Account: "a", Password: "b"}),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.sid, "")
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+}
+
+func TestClientCookieJar(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	ensure.Nil(t, err)
 	c, err := NewClient(
 		ClientRawURL("http://foo.com/"),
+		ClientCookieJar(jar),
 		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
 			return &http.Response{
-				Body: ioutil.NopCloser(strings.NewReader(".")),
+				Header: http.Header{"Set-Cookie": []string{"a=b"}},
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+				})),
 			}, nil
 		})),
 	)
 	ensure.Nil(t, err)
-	err = c.Do(context.Background(), &Request{}, nil)
-	ensure.Err(t, err, regexp.MustCompile("invalid character"))
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+	u, err := url.Parse("http://foo.com/webapi/entry.cgi")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(jar.Cookies(u)), 1)
 }
 
-func TestClientDoDataJSONError(t *testing.T) {
+func TestClientSetSID(t *testing.T) {
+	c, err := NewClient(ClientRawURL("http://foo.com/"), ClientSID("a"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.SID(), "a")
+	c.SetSID("b")
+	ensure.DeepEqual(t, c.SID(), "b")
+}
+
+func TestClientWithSID(t *testing.T) {
 	c, err := NewClient(
 		ClientRawURL("http://foo.com/"),
+		ClientSID("a"),
+		ClientAutoRelogin(AuthLogin{Account: "a"}),
 		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v["_sid"], []string{"b"})
+			ensure.DeepEqual(t, v["SynoToken"], []string{"token"})
 			return &http.Response{
 				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
 					"success": true,
-					"data":    true,
 				})),
 			}, nil
 		})),
 	)
 	ensure.Nil(t, err)
-	var res string
-	err = c.Do(context.Background(), &Request{}, &res)
-	ensure.Err(t, err, regexp.MustCompile("cannot unmarshal bool into Go value of type string"))
+	c.storeLogin(SessionFileStation, false, &AuthLoginResponse{
+		SID:       "b",
+		SynoToken: "token",
+	})
+	n := c.WithSID("b")
+	ensure.DeepEqual(t, n.SID(), "b")
+	ensure.DeepEqual(t, c.SID(), "a")
+	ensure.True(t, n.credentials == nil)
+	ensure.False(t, n.autoRelogin)
+	ensure.True(t, n.sessions == nil)
+	ensure.DeepEqual(t, n.url, c.url)
+	ensure.Nil(t, n.Do(context.Background(), &Request{Session: SessionFileStation}, nil))
 }
 
-func TestClientAPIError(t *testing.T) {
+func TestClientConcurrentSID(t *testing.T) {
 	c, err := NewClient(
 		ClientRawURL("http://foo.com/"),
 		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
 			return &http.Response{
 				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
-					"error": map[string]interface{}{
-						"code": ErrorUnknown,
-					},
+					"success": true,
 				})),
 			}, nil
 		})),
 	)
 	ensure.Nil(t, err)
-	err = c.Do(context.Background(), &Request{}, nil)
-	ensure.DeepEqual(t, err, ErrorUnknown)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.SetSID(fmt.Sprint(i))
+			ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+			c.WithSID("x").SID()
+		}(i)
+	}
+	wg.Wait()
 }
 
-func TestClientLogin(t *testing.T) {
+func TestClientSession(t *testing.T) {
+	var logins int
 	c, err := NewClient(
 		ClientRawURL("http://foo.com/"),
+		ClientSession(SessionDownloadStation),
 		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
 			v, err := url.ParseQuery(r.URL.RawQuery)
 			ensure.Nil(t, err)
-			ensure.Subset(t, v, url.Values{
-				"account": []string{"account"},
-				"passwd":  []string{"password"},
-			})
+			res := map[string]interface{}{"success": true}
+			switch v.Get("method") {
+			case "login":
+				logins++
+				ensure.DeepEqual(t, v["session"], []string{SessionDownloadStation})
+				res["data"] = map[string]string{"sid": "sid"}
+			case "logout":
+				ensure.DeepEqual(t, v["session"], []string{SessionDownloadStation})
+			default:
+				ensure.DeepEqual(t, v["_sid"], []string{"sid"})
+			}
 			return &http.Response{
-				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
-					"success": true,
-					"data": map[string]string{
-						"sid": "sid",
-					},
-				})),
+				Body: ioutil.NopCloser(jsonpipe.Encode(res)),
 			}, nil
 		})),
-		ClientLogin(AuthLogin{
-			Account:  "account",
-			Password: "password",
-		}),
+		ClientCredentials(AuthLogin{Account: "a", Password: "b"}),
+		ClientLogin(AuthLogin{Account: "a", Password: "b"}),
 	)
 	ensure.Nil(t, err)
-	ensure.DeepEqual(t, c.sid, "sid")
+	ensure.DeepEqual(t, c.sessions, map[string]string{SessionDownloadStation: "sid"})
+	ensure.Nil(t, c.Call(context.Background(), DownloadTaskList{}, nil))
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+	ensure.DeepEqual(t, logins, 1)
+	ensure.Nil(t, c.Close(context.Background()))
 }
 
-func TestClientLoginError(t *testing.T) {
-	givenErr := errors.New("")
+func TestClientSessionLogin(t *testing.T) {
 	c, err := NewClient(
 		ClientRawURL("http://foo.com/"),
+		ClientSession(SessionFileStation),
+		ClientAutoRelogin(AuthLogin{Account: "a"}),
 		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
-			return nil, givenErr
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, v["session"], []string{SessionFileStation})
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    map[string]string{"sid": "sid"},
+				})),
+			}, nil
 		})),
-		ClientLogin(AuthLogin{
-			Account:  "account",
-			Password: "password",
-		}),
 	)
-	ensure.True(t, c == nil)
-	ensure.DeepEqual(t, err, givenErr)
+	ensure.Nil(t, err)
+	ensure.Nil(t, c.Login(context.Background()))
+	ensure.DeepEqual(t, c.SID(), "sid")
 }
 
 type funcMarshalRequest func() (*Request, error)
@@ -260,6 +1033,86 @@ func TestCallTransportError(t *testing.T) {
 	ensure.DeepEqual(t, err, givenErr)
 }
 
+func TestCallTyped(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(jsonpipe.Encode(map[string]interface{}{
+					"success": true,
+					"data":    map[string]string{"sid": "sid"},
+				})),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ctx := context.Background()
+	res, err := CallTyped[AuthLoginResponse](ctx, c, AuthLogin{Account: "a"})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, AuthLoginResponse{SID: "sid"})
+	m, err := DoTyped[map[string]string](ctx, c, &Request{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, m, map[string]string{"sid": "sid"})
+}
+
+func TestCallTypedError(t *testing.T) {
+	givenErr := errors.New("")
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{"data":{"sid":"sid"}}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	ctx := context.Background()
+	res, err := CallTyped[*AuthLoginResponse](ctx, c, AuthLogin{Account: "a"})
+	ensure.NotNil(t, err)
+	ensure.True(t, res == nil)
+	_, err = CallTyped[AuthLoginResponse](
+		ctx,
+		c,
+		funcMarshalRequest(func() (*Request, error) { return nil, givenErr }),
+	)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestClientDoRaw(t *testing.T) {
+	body := `{"success":false,"error":{"code":408,"errors":[{"path":"/a"}]}}`
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	res, err := c.DoRaw(context.Background(), &Request{})
+	ensure.True(t, errors.Is(err, Error(408)))
+	ensure.DeepEqual(t, res, &Response{
+		Error: ResponseError{
+			Code:   408,
+			Errors: json.RawMessage(`[{"path":"/a"}]`),
+			Details: []ErrorDetail{
+				{Path: "/a", Raw: json.RawMessage(`{"path":"/a"}`)},
+			},
+		},
+		Body: []byte(body),
+	})
+
+	body = `{"success":true,"data":{"a":1}}`
+	res, err = c.DoRaw(context.Background(), &Request{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(res.Data), `{"a":1}`)
+
+	body = `<html>`
+	res, err = c.DoRaw(context.Background(), &Request{})
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, string(res.Body), body)
+}
+
 func TestNewClientURLNil(t *testing.T) {
 	c, err := NewClient()
 	ensure.True(t, c == nil)
@@ -311,6 +1164,42 @@ func TestAuthLoginMarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			AuthLogin: AuthLogin{
+				Account:         "a",
+				EnableSynoToken: true,
+			},
+			Request: &Request{
+				Path:    authLoginPath,
+				API:     authLoginAPI,
+				Version: authLoginVersion,
+				Method:  "login",
+				Params: url.Values{
+					"account":           []string{"a"},
+					"enable_syno_token": []string{"yes"},
+				},
+			},
+		},
+		{
+			AuthLogin: AuthLogin{
+				OTPCode:           "123456",
+				EnableDeviceToken: true,
+				DeviceName:        "d",
+				DeviceID:          "i",
+			},
+			Request: &Request{
+				Path:    authLoginPath,
+				API:     authLoginAPI,
+				Version: authLoginVersion,
+				Method:  "login",
+				Params: url.Values{
+					"otp_code":            []string{"123456"},
+					"enable_device_token": []string{"yes"},
+					"device_name":         []string{"d"},
+					"device_id":           []string{"i"},
+				},
+			},
+		},
 		{
 			AuthLogin: AuthLogin{},
 			Request: &Request{
@@ -330,6 +1219,19 @@ func TestAuthLoginMarshal(t *testing.T) {
 	}
 }
 
+func TestAuthLogoutMarshal(t *testing.T) {
+	r, err := AuthLogout{Session: "a", SID: "b"}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    authLoginPath,
+		API:     authLoginAPI,
+		Version: authLoginVersion,
+		Method:  "logout",
+		Params:  url.Values{"session": []string{"a"}},
+		SID:     "b",
+	})
+}
+
 func TestDownloadTaskListMarshal(t *testing.T) {
 	cases := []struct {
 		DownloadTaskList DownloadTaskList
@@ -351,6 +1253,7 @@ func TestDownloadTaskListMarshal(t *testing.T) {
 					"limit":      []string{"2"},
 					"additional": []string{"a,b"},
 				},
+				Session: SessionDownloadStation,
 			},
 		},
 		{
@@ -361,6 +1264,7 @@ func TestDownloadTaskListMarshal(t *testing.T) {
 				Version: downloadTaskVersion,
 				Method:  "list",
 				Params:  url.Values{},
+				Session: SessionDownloadStation,
 			},
 		},
 	}
@@ -372,6 +1276,81 @@ func TestDownloadTaskListMarshal(t *testing.T) {
 	}
 }
 
+func TestDownloadTaskGetInfoMarshal(t *testing.T) {
+	r, err := DownloadTaskGetInfo{
+		IDs:        []string{"dbid_1", "dbid_2"},
+		Additional: []string{DownloadTaskAdditionalTransfer},
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:    downloadTaskPath,
+		API:     downloadTaskAPI,
+		Version: downloadTaskVersion,
+		Method:  "getinfo",
+		Params: url.Values{
+			"id":         []string{"dbid_1,dbid_2"},
+			"additional": []string{"transfer"},
+		},
+		Session: SessionDownloadStation,
+	})
+}
+
+func TestDownloadTaskAdditionalUnmarshal(t *testing.T) {
+	var task DownloadTask
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"id": "dbid_1",
+		"type": "bt",
+		"additional": {
+			"file": [{
+				"filename": "a.mkv",
+				"size": "1024",
+				"size_downloaded": 512,
+				"priority": "normal"
+			}],
+			"tracker": [{
+				"url": "udp://t",
+				"status": "Success",
+				"update_timer": 1800,
+				"seeds": 3,
+				"peers": 7
+			}],
+			"peer": [{
+				"address": "1.2.3.4:6881",
+				"agent": "qBittorrent",
+				"progress": 0.5,
+				"speed_download": 10,
+				"speed_upload": "20"
+			}]
+		}
+	}`), &task))
+	ensure.DeepEqual(t, task, DownloadTask{
+		ID:   "dbid_1",
+		Type: DownloadTaskBT,
+		Additional: &DownloadTaskAdditional{
+			File: []DownloadTaskFile{{
+				Filename:       "a.mkv",
+				Size:           1024,
+				SizeDownloaded: 512,
+				Priority:       "normal",
+			}},
+			Tracker: []DownloadTaskTracker{{
+				URL:         "udp://t",
+				Status:      "Success",
+				Seeds:       3,
+				Peers:       7,
+				UpdateTimer: 1800,
+			}},
+			Peer: []DownloadTaskPeer{{
+				Address:       "1.2.3.4:6881",
+				Agent:         "qBittorrent",
+				Progress:      0.5,
+				SpeedDownload: 10,
+				SpeedUpload:   20,
+			}},
+		},
+	})
+}
+
 func TestDownloadTaskCreateMarshal(t *testing.T) {
 	cases := []struct {
 		DownloadTaskCreate DownloadTaskCreate
@@ -386,10 +1365,11 @@ func TestDownloadTaskCreateMarshal(t *testing.T) {
 				Destination:   "e",
 			},
 			Request: &Request{
-				Path:    downloadTaskPath,
-				API:     downloadTaskAPI,
-				Version: downloadTaskVersion,
-				Method:  "create",
+				Path:       downloadTaskPath,
+				API:        downloadTaskAPI,
+				Version:    downloadTaskVersion,
+				Method:     "create",
+				HTTPMethod: "POST",
 				Params: url.Values{
 					"uri":            []string{"a"},
 					"username":       []string{"b"},
@@ -397,6 +1377,7 @@ func TestDownloadTaskCreateMarshal(t *testing.T) {
 					"unzip_password": []string{"d"},
 					"destination":    []string{"e"},
 				},
+				Session: SessionDownloadStation,
 			},
 		},
 		{
@@ -404,11 +1385,13 @@ func TestDownloadTaskCreateMarshal(t *testing.T) {
 				URI: "foo",
 			},
 			Request: &Request{
-				Path:    downloadTaskPath,
-				API:     downloadTaskAPI,
-				Version: downloadTaskVersion,
-				Method:  "create",
-				Params:  url.Values{"uri": []string{"foo"}},
+				Path:       downloadTaskPath,
+				API:        downloadTaskAPI,
+				Version:    downloadTaskVersion,
+				Method:     "create",
+				HTTPMethod: "POST",
+				Params:     url.Values{"uri": []string{"foo"}},
+				Session:    SessionDownloadStation,
 			},
 		},
 	}
@@ -419,3 +1402,208 @@ func TestDownloadTaskCreateMarshal(t *testing.T) {
 		ensure.DeepEqual(t, r, c.Request)
 	}
 }
+
+func TestDownloadTaskCreateFileMarshal(t *testing.T) {
+	f := strings.NewReader("torrent")
+	r, err := DownloadTaskCreate{File: f, FileName: "a.torrent"}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r.Params, url.Values{})
+	ensure.DeepEqual(t, r.Files, []RequestFile{
+		{Name: "file", Filename: "a.torrent", Body: f},
+	})
+}
+
+func TestDownloadTaskDeleteMarshal(t *testing.T) {
+	cases := []struct {
+		DownloadTaskDelete DownloadTaskDelete
+		Params             url.Values
+	}{
+		{
+			DownloadTaskDelete: DownloadTaskDelete{IDs: []string{"dbid_1"}},
+			Params:             url.Values{"id": []string{"dbid_1"}},
+		},
+		{
+			DownloadTaskDelete: DownloadTaskDelete{
+				IDs:           []string{"dbid_1", "dbid_2"},
+				ForceComplete: true,
+			},
+			Params: url.Values{
+				"id":             []string{"dbid_1,dbid_2"},
+				"force_complete": []string{"true"},
+			},
+		},
+	}
+	for _, c := range cases {
+		r, err := c.DownloadTaskDelete.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:       downloadTaskPath,
+			API:        downloadTaskAPI,
+			Version:    downloadTaskVersion,
+			Method:     "delete",
+			HTTPMethod: "POST",
+			Params:     c.Params,
+			Session:    SessionDownloadStation,
+		})
+	}
+}
+
+func TestDownloadTaskPauseResumeMarshal(t *testing.T) {
+	cases := []struct {
+		Request MarshalRequest
+		Method  string
+	}{
+		{DownloadTaskPause{IDs: []string{"dbid_1", "dbid_2"}}, "pause"},
+		{DownloadTaskResume{IDs: []string{"dbid_1", "dbid_2"}}, "resume"},
+	}
+	for _, c := range cases {
+		r, err := c.Request.MarshalRequest()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, r, &Request{
+			Path:       downloadTaskPath,
+			API:        downloadTaskAPI,
+			Version:    downloadTaskVersion,
+			Method:     c.Method,
+			HTTPMethod: "POST",
+			Params:     url.Values{"id": []string{"dbid_1,dbid_2"}},
+			Session:    SessionDownloadStation,
+		})
+	}
+}
+
+func TestDownloadTaskEditMarshal(t *testing.T) {
+	r, err := DownloadTaskEdit{
+		IDs:         []string{"dbid_1"},
+		Destination: "video/movies",
+	}.MarshalRequest()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, r, &Request{
+		Path:       downloadTaskPath,
+		API:        downloadTaskAPI,
+		Version:    "2",
+		Method:     "edit",
+		HTTPMethod: "POST",
+		Params: url.Values{
+			"id":          []string{"dbid_1"},
+			"destination": []string{"video/movies"},
+		},
+		Session: SessionDownloadStation,
+	})
+}
+
+func TestClientDownloadTaskPause(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": true,
+					"data": [{"error": 0, "id": "dbid_1"}, {"error": 405, "id": "dbid_2"}]
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	var res DownloadTaskResults
+	err = c.Call(
+		context.Background(),
+		DownloadTaskPause{IDs: []string{"dbid_1", "dbid_2"}},
+		&res,
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, DownloadTaskResults{
+		{ID: "dbid_1"},
+		{ID: "dbid_2", Error: ErrorDownloadInvalidTaskAction},
+	})
+}
+
+func TestDownloadTaskResults(t *testing.T) {
+	var res DownloadTaskResults
+	ensure.Nil(t, json.Unmarshal(
+		[]byte(`[{"error":0,"id":"dbid_1"},{"error":404,"id":"dbid_2"}]`),
+		&res,
+	))
+	ensure.DeepEqual(t, res, DownloadTaskResults{
+		{ID: "dbid_1"},
+		{ID: "dbid_2", Error: ErrorDownloadInvalidTaskID},
+	})
+	ensure.Nil(t, res[0].Err())
+	ensure.DeepEqual(t, res[1].Err(), ErrorDownloadInvalidTaskID)
+}
+
+func TestAuthErrorStrings(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		ErrorString(authLoginAPI, ErrorAuthOTPRequired),
+		"syno: 2-step verification code required (403)",
+	)
+	ensure.DeepEqual(
+		t,
+		ErrorString(authLoginAPI, ErrorAuthNoSuchAccount),
+		"syno: no such account or incorrect password (400)",
+	)
+	ensure.DeepEqual(t, ErrorAuthOTPRequired.Error(), "syno: error code 403")
+}
+
+func TestIsOTPRequired(t *testing.T) {
+	ensure.True(t, IsOTPRequired(ErrorAuthOTPRequired))
+	ensure.True(t, IsOTPRequired(fmt.Errorf("login: %w", ErrorAuthOTPRequired)))
+	ensure.False(t, IsOTPRequired(ErrorAuthOTPFailed))
+	ensure.False(t, IsOTPRequired(errors.New("")))
+	ensure.False(t, IsOTPRequired(nil))
+}
+
+func TestDownloadStationErrorStrings(t *testing.T) {
+	ensure.DeepEqual(
+		t,
+		ErrorString(downloadTaskAPI, ErrorDownloadInvalidTaskID),
+		"syno: invalid task id (404)",
+	)
+	ensure.DeepEqual(
+		t,
+		ErrorString(authLoginAPI, ErrorDownloadInvalidTaskID),
+		"syno: failed to authenticate 2-step verification code (404)",
+	)
+}
+
+func TestClientDoNestedErrors(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"success": false,
+					"error": {
+						"code": 900,
+						"errors": [
+							{"code": 408, "path": "/a"},
+							{"code": 407, "path": "/b", "reason": "x"},
+							"invalid"
+						]
+					}
+				}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Do(context.Background(), &Request{}, nil)
+	ensure.True(t, errors.Is(err, Error(900)))
+	var re *ResponseError
+	ensure.True(t, errors.As(err, &re))
+	ensure.DeepEqual(t, len(re.Details), 2)
+	ensure.DeepEqual(t, re.Details[1].Code, Error(407))
+	ensure.DeepEqual(t, string(re.Details[1].Raw), `{"code": 407, "path": "/b", "reason": "x"}`)
+	ensure.DeepEqual(
+		t,
+		err.Error(),
+		"syno: error code 900: /a: error code 408; /b: error code 407",
+	)
+}
+
+func TestResponseErrorUnmarshal(t *testing.T) {
+	var e ResponseError
+	ensure.Nil(t, json.Unmarshal([]byte(`{"code":1,"errors":{"a":1}}`), &e))
+	ensure.True(t, e.Details == nil)
+	ensure.DeepEqual(t, e.err(), Error(1))
+	ensure.NotNil(t, json.Unmarshal([]byte(`{"code":"x"}`), &e))
+}
@@ -0,0 +1,54 @@
+package syno
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// maxUnixSeconds separates timestamps in seconds from those in milliseconds.
+// It is in the year 5138 when read as seconds, and in 1973 as milliseconds.
+const maxUnixSeconds = 1e11
+
+// Time is a time.Time decoded from the Unix timestamps returned by the APIs.
+// Depending on the API, they are seconds or milliseconds, as numbers or quoted
+// strings. Zero and empty timestamps decode to the zero Time.
+type Time struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes a Unix timestamp in seconds or milliseconds, given as
+// a number or a string.
+func (t *Time) UnmarshalJSON(b []byte) error {
+	b = bytes.Trim(b, `"`)
+	if len(b) == 0 || string(b) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		var f float64
+		if err := json.Unmarshal(b, &f); err != nil {
+			return err
+		}
+		n = int64(f)
+	}
+	switch {
+	case n == 0:
+		t.Time = time.Time{}
+	case n > maxUnixSeconds || n < -maxUnixSeconds:
+		t.Time = time.UnixMilli(n)
+	default:
+		t.Time = time.Unix(n, 0)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the Time as a Unix timestamp in seconds.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("0"), nil
+	}
+	return strconv.AppendInt(nil, t.Unix(), 10), nil
+}
@@ -0,0 +1,52 @@
+package syno
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestTimeUnmarshal(t *testing.T) {
+	cases := []struct {
+		JSON string
+		Time time.Time
+	}{
+		{JSON: `1500000000`, Time: time.Unix(1500000000, 0)},
+		{JSON: `"1500000000"`, Time: time.Unix(1500000000, 0)},
+		{JSON: `1500000000123`, Time: time.UnixMilli(1500000000123)},
+		{JSON: `"1500000000123"`, Time: time.UnixMilli(1500000000123)},
+		{JSON: `1500000000.0`, Time: time.Unix(1500000000, 0)},
+		{JSON: `0`, Time: time.Time{}},
+		{JSON: `""`, Time: time.Time{}},
+		{JSON: `null`, Time: time.Time{}},
+	}
+	for _, c := range cases {
+		var v Time
+		ensure.Nil(t, json.Unmarshal([]byte(c.JSON), &v), c.JSON)
+		ensure.True(t, v.Equal(c.Time), c.JSON, v)
+	}
+}
+
+func TestTimeUnmarshalError(t *testing.T) {
+	var v Time
+	ensure.NotNil(t, json.Unmarshal([]byte(`"yesterday"`), &v))
+}
+
+func TestTimeMarshal(t *testing.T) {
+	b, err := json.Marshal([]Time{{}, {Time: time.Unix(1500000000, 0)}})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), `[0,1500000000]`)
+}
+
+func TestDownloadTaskDetailUnmarshal(t *testing.T) {
+	var task DownloadTask
+	err := json.Unmarshal([]byte(`{
+		"id": "dbid_1",
+		"additional": {"detail": {"create_time": "1500000000", "completed_time": 0}}
+	}`), &task)
+	ensure.Nil(t, err)
+	ensure.True(t, task.Additional.Detail.CreateTime.Equal(time.Unix(1500000000, 0)))
+	ensure.True(t, task.Additional.Detail.CompletedTime.IsZero())
+}
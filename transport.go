@@ -0,0 +1,161 @@
+package syno
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var errTransportNotConfigurable = errors.New(
+	"syno: transport options require an *http.Transport")
+
+// httpTransport returns the *http.Transport used by the Client so it can be
+// configured. The first call clones the configured transport, so neither
+// http.DefaultTransport nor one given to ClientTransport are modified.
+func (c *Client) httpTransport() (*http.Transport, error) {
+	t, ok := c.transport.(*http.Transport)
+	if !ok {
+		return nil, errTransportNotConfigurable
+	}
+	if !c.ownTransport {
+		t = t.Clone()
+		c.transport = t
+		c.ownTransport = true
+	}
+	return t, nil
+}
+
+// ClientProxy configures the function used to select the proxy for a request,
+// as with http.Transport.Proxy.
+func ClientProxy(proxy func(*http.Request) (*url.URL, error)) ClientOption {
+	return func(c *Client) error {
+		t, err := c.httpTransport()
+		if err != nil {
+			return err
+		}
+		t.Proxy = proxy
+		return nil
+	}
+}
+
+// ClientProxyURL configures all requests to be made via the proxy at the given
+// URL.
+func ClientProxyURL(proxy string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return err
+		}
+		return ClientProxy(http.ProxyURL(u))(c)
+	}
+}
+
+// tlsConfig returns the TLS configuration of the Client's transport so it can
+// be modified, creating one if necessary.
+func (c *Client) tlsConfig() (*tls.Config, error) {
+	t, err := c.httpTransport()
+	if err != nil {
+		return nil, err
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig, nil
+}
+
+// ClientTLSConfig configures the TLS configuration used by the transport. The
+// configuration is cloned, so later changes to it have no effect.
+func ClientTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) error {
+		t, err := c.httpTransport()
+		if err != nil {
+			return err
+		}
+		t.TLSClientConfig = cfg.Clone()
+		return nil
+	}
+}
+
+// ClientRootCAs configures the certificate authorities used to verify the
+// server, such as one containing the self-signed certificate of a NAS.
+func ClientRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) error {
+		cfg, err := c.tlsConfig()
+		if err != nil {
+			return err
+		}
+		cfg.RootCAs = pool
+		return nil
+	}
+}
+
+// ClientCertificates configures the certificates presented to servers
+// requiring client certificate authentication.
+func ClientCertificates(certs ...tls.Certificate) ClientOption {
+	return func(c *Client) error {
+		cfg, err := c.tlsConfig()
+		if err != nil {
+			return err
+		}
+		cfg.Certificates = certs
+		return nil
+	}
+}
+
+// ClientInsecureSkipVerify disables verification of the server certificate.
+// This makes the connection susceptible to man-in-the-middle attacks, and
+// ClientRootCAs should be preferred for self-signed certificates.
+func ClientInsecureSkipVerify() ClientOption {
+	return func(c *Client) error {
+		cfg, err := c.tlsConfig()
+		if err != nil {
+			return err
+		}
+		cfg.InsecureSkipVerify = true
+		return nil
+	}
+}
+
+// ClientMaxIdleConnsPerHost configures the number of idle connections kept
+// open to the server, as with http.Transport.MaxIdleConnsPerHost. Clients
+// polling with concurrent requests benefit from raising it above the default
+// of 2.
+func ClientMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *Client) error {
+		t, err := c.httpTransport()
+		if err != nil {
+			return err
+		}
+		t.MaxIdleConnsPerHost = n
+		return nil
+	}
+}
+
+// ClientIdleConnTimeout configures how long idle connections are kept open,
+// as with http.Transport.IdleConnTimeout.
+func ClientIdleConnTimeout(d time.Duration) ClientOption {
+	return func(c *Client) error {
+		t, err := c.httpTransport()
+		if err != nil {
+			return err
+		}
+		t.IdleConnTimeout = d
+		return nil
+	}
+}
+
+// ClientDisableKeepAlives configures the transport to use a new connection
+// for every request, such as for clients that only rarely make requests.
+func ClientDisableKeepAlives() ClientOption {
+	return func(c *Client) error {
+		t, err := c.httpTransport()
+		if err != nil {
+			return err
+		}
+		t.DisableKeepAlives = true
+		return nil
+	}
+}
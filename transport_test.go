@@ -0,0 +1,207 @@
+package syno
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestHTTPTransportClonesDefault(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientProxyURL("http://proxy.com:8080"),
+	)
+	ensure.Nil(t, err)
+	ensure.True(t, c.transport != http.DefaultTransport)
+	ensure.True(t, http.DefaultTransport.(*http.Transport).Proxy != nil)
+	proxy, err := c.transport.(*http.Transport).Proxy(&http.Request{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, proxy.String(), "http://proxy.com:8080")
+}
+
+func TestHTTPTransportClonesGiven(t *testing.T) {
+	given := &http.Transport{}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(given),
+		ClientProxy(http.ProxyFromEnvironment),
+		ClientProxyURL("http://proxy.com:8080"),
+	)
+	ensure.Nil(t, err)
+	ensure.True(t, given.Proxy == nil)
+	ensure.True(t, c.transport.(*http.Transport).Proxy != nil)
+}
+
+func TestHTTPTransportNotConfigurable(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(nil)),
+		ClientProxy(nil),
+	)
+	ensure.True(t, c == nil)
+	ensure.DeepEqual(t, err, errTransportNotConfigurable)
+}
+
+func TestClientProxyURLInvalid(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientProxyURL(":"),
+	)
+	ensure.True(t, c == nil)
+	_, ok := err.(*url.Error)
+	ensure.True(t, ok)
+}
+
+func TestClientTLSOptions(t *testing.T) {
+	pool := x509.NewCertPool()
+	given := &tls.Config{ServerName: "nas"}
+	c, err := NewClient(
+		ClientRawURL("https://foo.com/"),
+		ClientTLSConfig(given),
+		ClientRootCAs(pool),
+		ClientCertificates(tls.Certificate{}),
+		ClientInsecureSkipVerify(),
+	)
+	ensure.Nil(t, err)
+	cfg := c.transport.(*http.Transport).TLSClientConfig
+	ensure.DeepEqual(t, cfg.ServerName, "nas")
+	ensure.True(t, cfg.RootCAs == pool)
+	ensure.DeepEqual(t, len(cfg.Certificates), 1)
+	ensure.True(t, cfg.InsecureSkipVerify)
+	ensure.True(t, given.RootCAs == nil)
+	ensure.False(t, given.InsecureSkipVerify)
+}
+
+func TestClientTLSOptionsNotConfigurable(t *testing.T) {
+	for _, o := range []ClientOption{
+		ClientTLSConfig(&tls.Config{}),
+		ClientRootCAs(nil),
+		ClientCertificates(),
+		ClientInsecureSkipVerify(),
+	} {
+		c, err := NewClient(
+			ClientRawURL("https://foo.com/"),
+			ClientTransport(transportFunc(nil)),
+			o,
+		)
+		ensure.True(t, c == nil)
+		ensure.DeepEqual(t, err, errTransportNotConfigurable)
+	}
+}
+
+func TestClientConnectionPoolOptions(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientMaxIdleConnsPerHost(8),
+		ClientIdleConnTimeout(time.Minute),
+		ClientDisableKeepAlives(),
+	)
+	ensure.Nil(t, err)
+	tr := c.transport.(*http.Transport)
+	ensure.DeepEqual(t, tr.MaxIdleConnsPerHost, 8)
+	ensure.DeepEqual(t, tr.IdleConnTimeout, time.Minute)
+	ensure.True(t, tr.DisableKeepAlives)
+}
+
+func TestClientConnectionPoolOptionsNotConfigurable(t *testing.T) {
+	options := []ClientOption{
+		ClientMaxIdleConnsPerHost(8),
+		ClientIdleConnTimeout(time.Minute),
+		ClientDisableKeepAlives(),
+	}
+	for _, o := range options {
+		c, err := NewClient(
+			ClientRawURL("http://foo.com/"),
+			ClientTransport(transportFunc(nil)),
+			o,
+		)
+		ensure.True(t, c == nil)
+		ensure.DeepEqual(t, err, errTransportNotConfigurable)
+	}
+}
+
+func TestClientHTTPClient(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	ensure.Nil(t, err)
+	var redirected bool
+	hc := &http.Client{
+		Jar: jar,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path != "/moved" {
+				return &http.Response{
+					StatusCode: http.StatusFound,
+					Header:     http.Header{"Location": []string{"/moved"}},
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+					Request:    r,
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Set-Cookie": []string{"id=cookie"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"success":true}`)),
+				Request:    r,
+			}, nil
+		}),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			redirected = true
+			return nil
+		},
+	}
+	c, err := NewClient(ClientRawURL("http://foo.com/"), ClientHTTPClient(hc))
+	ensure.Nil(t, err)
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+	ensure.True(t, redirected)
+	u, _ := url.Parse("http://foo.com/")
+	ensure.DeepEqual(t, len(jar.Cookies(u)), 1)
+}
+
+func TestClientHTTPClientTransportOptions(t *testing.T) {
+	given := &http.Transport{}
+	hc := &http.Client{Transport: given}
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientHTTPClient(hc),
+		ClientProxyURL("http://proxy.com:8080"),
+	)
+	ensure.Nil(t, err)
+	ensure.True(t, given.Proxy == nil)
+	ensure.True(t, hc.Transport == given)
+	ensure.True(t, c.transport.(*http.Transport).Proxy != nil)
+
+	c, err = NewClient(ClientRawURL("http://foo.com/"), ClientHTTPClient(&http.Client{}))
+	ensure.Nil(t, err)
+	ensure.True(t, c.transport == http.DefaultTransport)
+}
+
+func TestClientHTTPClientCookieJar(t *testing.T) {
+	jar, err := cookiejar.New(nil)
+	ensure.Nil(t, err)
+	var cookie string
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientCookieJar(jar),
+		ClientHTTPClient(&http.Client{
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				cookie = r.Header.Get("Cookie")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`{"success":true}`)),
+				}, nil
+			}),
+		}),
+	)
+	ensure.Nil(t, err)
+	u, _ := url.Parse("http://foo.com/")
+	jar.SetCookies(u, []*http.Cookie{{Name: "id", Value: "a"}})
+	ensure.Nil(t, c.Do(context.Background(), &Request{}, nil))
+	ensure.DeepEqual(t, cookie, "id=a")
+}
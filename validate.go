@@ -0,0 +1,45 @@
+package syno
+
+import "fmt"
+
+// Validator can be implemented by a MarshalRequest to check it before it is
+// sent. Call runs Validate before marshaling the request, and fails without
+// making the request if it returns an error.
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError is returned by Validate implementations for requests that
+// are missing required fields or combine incompatible ones.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("syno: invalid %s: %s", e.Field, e.Reason)
+}
+
+func validate(r MarshalRequest) error {
+	if v, ok := r.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// requireField returns a ValidationError if the value is empty.
+func requireField(field, value string) error {
+	if value == "" {
+		return &ValidationError{Field: field, Reason: "required"}
+	}
+	return nil
+}
+
+// requireWith returns a ValidationError if the field is set without the
+// other one it depends on.
+func requireWith(field string, set bool, other, value string) error {
+	if set && value == "" {
+		return &ValidationError{Field: field, Reason: "requires " + other}
+	}
+	return nil
+}
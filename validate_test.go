@@ -0,0 +1,351 @@
+package syno
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestValidate(t *testing.T) {
+	unknownSource := SurveillanceMotionSource(2)
+	cases := []struct {
+		Request MarshalRequest
+		Err     error
+	}{
+		{
+			Request: DownloadTaskCreate{},
+			Err:     &ValidationError{Field: "URI", Reason: "required"},
+		},
+		{Request: DownloadTaskCreate{URI: "a"}},
+		{Request: DownloadTaskCreate{File: strings.NewReader("")}},
+		{
+			Request: DownloadTaskCreate{URI: "a", File: strings.NewReader("")},
+			Err:     &ValidationError{Field: "File", Reason: "conflicts with URI"},
+		},
+		{
+			Request: AuthLogin{EnableDeviceToken: true},
+			Err: &ValidationError{
+				Field:  "EnableDeviceToken",
+				Reason: "requires OTPCode",
+			},
+		},
+		{Request: AuthLogin{EnableDeviceToken: true, OTPCode: "1"}},
+		{
+			Request: AuthSSOLogin{},
+			Err:     &ValidationError{Field: "AccessToken", Reason: "required"},
+		},
+		{
+			Request: EncryptedRequest{Request: DownloadTaskCreate{}},
+			Err:     &ValidationError{Field: "URI", Reason: "required"},
+		},
+		{Request: DownloadTaskList{}},
+		{
+			Request: DownloadTaskDelete{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{Request: DownloadTaskDelete{IDs: []string{"a"}}},
+		{
+			Request: DownloadTaskPause{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: DownloadTaskResume{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: DownloadTaskEdit{Destination: "a"},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: DownloadTaskEdit{IDs: []string{"a"}},
+			Err:     &ValidationError{Field: "Destination", Reason: "required"},
+		},
+		{
+			Request: DownloadBTSearchStart{},
+			Err:     &ValidationError{Field: "Keyword", Reason: "required"},
+		},
+		{
+			Request: DownloadBTSearchList{},
+			Err:     &ValidationError{Field: "TaskID", Reason: "required"},
+		},
+		{
+			Request: DownloadBTSearchClean{},
+			Err:     &ValidationError{Field: "TaskIDs", Reason: "required"},
+		},
+		{
+			Request: DownloadEMuleTaskCreate{},
+			Err:     &ValidationError{Field: "Links", Reason: "required"},
+		},
+		{
+			Request: DownloadEMuleTaskCreate{Links: []string{"http://a"}},
+			Err: &ValidationError{
+				Field:  "Links",
+				Reason: "not an ed2k link: http://a",
+			},
+		},
+		{Request: DownloadEMuleTaskCreate{Links: []string{"ED2K://|file|a|1|h|/"}}},
+		{
+			Request: DownloadTask2Create{},
+			Err:     &ValidationError{Field: "URLs", Reason: "required"},
+		},
+		{
+			Request: DownloadTask2Create{URLs: []string{"a"}, File: strings.NewReader("")},
+			Err:     &ValidationError{Field: "File", Reason: "conflicts with URLs"},
+		},
+		{
+			Request: DownloadTask2Delete{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: FileStationList{},
+			Err:     &ValidationError{Field: "FolderPath", Reason: "required"},
+		},
+		{
+			Request: FileStationSearchStart{},
+			Err:     &ValidationError{Field: "FolderPaths", Reason: "required"},
+		},
+		{
+			Request: FileStationSearchList{},
+			Err:     &ValidationError{Field: "TaskID", Reason: "required"},
+		},
+		{
+			Request: FileStationCheckPermission{Filename: "a"},
+			Err:     &ValidationError{Field: "Path", Reason: "required"},
+		},
+		{
+			Request: FileStationCheckPermission{Path: "/a"},
+			Err:     &ValidationError{Field: "Filename", Reason: "required"},
+		},
+		{
+			Request: FileStationMountRemote{
+				Type:       VirtualFolderISO,
+				Server:     "//a/b",
+				MountPoint: "/c",
+			},
+			Err: &ValidationError{Field: "Type", Reason: "must be cifs or nfs"},
+		},
+		{
+			Request: FileStationMountRemote{Type: VirtualFolderCIFS, MountPoint: "/c"},
+			Err:     &ValidationError{Field: "Server", Reason: "required"},
+		},
+		{
+			Request: FileStationMountRemote{Type: VirtualFolderNFS, Server: "a:/b"},
+			Err:     &ValidationError{Field: "MountPoint", Reason: "required"},
+		},
+		{
+			Request: FileStationUnmount{Type: VirtualFolderCIFS},
+			Err:     &ValidationError{Field: "MountPoint", Reason: "required"},
+		},
+		{
+			Request: FileStationDelete{},
+			Err:     &ValidationError{Field: "Paths", Reason: "required"},
+		},
+		{
+			Request: FileStationGetACL{},
+			Err:     &ValidationError{Field: "Path", Reason: "required"},
+		},
+		{
+			Request: FileStationSetACL{},
+			Err:     &ValidationError{Field: "Path", Reason: "required"},
+		},
+		{
+			Request: FileStationSetOwner{Path: "/a"},
+			Err:     &ValidationError{Field: "Owner", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraEnable{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraDisable{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraGetCapability{},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraGetSnapshot{},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraGetSnapshot{CameraID: 1, Profile: 3},
+			Err:     &ValidationError{Field: "Profile", Reason: "unknown profile"},
+		},
+		{
+			Request: SurveillanceCameraGetLiveViewPath{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraGetStreamPath{},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceRecordingDownload{},
+			Err:     &ValidationError{Field: "RecordingID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceRecordingDownload{RecordingID: 1, Offset: -1},
+			Err:     &ValidationError{Field: "Offset", Reason: "must not be negative"},
+		},
+		{
+			Request: SurveillancePTZMove{Direction: SurveillancePTZUp},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillancePTZMove{CameraID: 1, Direction: "north"},
+			Err:     &ValidationError{Field: "Direction", Reason: "unknown direction"},
+		},
+		{
+			Request: SurveillancePTZZoom{CameraID: 1},
+			Err:     &ValidationError{Field: "Control", Reason: "must be in or out"},
+		},
+		{
+			Request: SurveillancePTZZoom{
+				CameraID: 1,
+				Control:  SurveillancePTZZoomOut,
+				MoveType: "start",
+			},
+			Err: &ValidationError{Field: "MoveType", Reason: "unknown move type"},
+		},
+		{
+			Request: SurveillancePTZGoPreset{},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceMotionGet{},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceMotionSet{
+				CameraID: 1,
+				Source:   &unknownSource,
+			},
+			Err: &ValidationError{Field: "Source", Reason: "unknown source"},
+		},
+		{
+			Request: SurveillanceBookmarkSave{RecordingID: 1, Name: "a"},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceBookmarkSave{CameraID: 1, Name: "a"},
+			Err:     &ValidationError{Field: "RecordingID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceBookmarkSave{CameraID: 1, RecordingID: 1},
+			Err:     &ValidationError{Field: "Name", Reason: "required"},
+		},
+		{
+			Request: SurveillanceBookmarkDelete{},
+			Err:     &ValidationError{Field: "IDs", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraTestConnection{},
+			Err:     &ValidationError{Field: "Host", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraTestConnection{
+				Camera: SurveillanceCameraConfig{Host: "a", Vendor: "b"},
+			},
+			Err: &ValidationError{Field: "Model", Reason: "required"},
+		},
+		{
+			Request: SurveillanceCameraSave{
+				Camera: SurveillanceCameraConfig{Host: "a", Vendor: "b", Model: "c"},
+			},
+			Err: &ValidationError{Field: "Name", Reason: "required"},
+		},
+		{
+			Request: SurveillanceNotificationFilterSet{},
+			Err:     &ValidationError{Field: "Filters", Reason: "required"},
+		},
+		{
+			Request: SurveillanceNotificationScheduleGet{},
+			Err:     &ValidationError{Field: "CameraID", Reason: "required"},
+		},
+		{
+			Request: SurveillanceNotificationScheduleSet{CameraID: 1},
+			Err:     &ValidationError{Field: "Schedule", Reason: "must have 7 days"},
+		},
+		{
+			Request: SurveillanceNotificationScheduleSet{
+				CameraID: 1,
+				Schedule: make([][]int, 7),
+			},
+			Err: &ValidationError{
+				Field:  "Schedule",
+				Reason: "must have 48 half hours a day",
+			},
+		},
+		{
+			Request: FileStationDownload{},
+			Err:     &ValidationError{Field: "Paths", Reason: "required"},
+		},
+		{
+			Request: FileStationCopyMoveStart{DestFolder: "/b"},
+			Err:     &ValidationError{Field: "Paths", Reason: "required"},
+		},
+		{
+			Request: FileStationCopyMoveStart{Paths: []string{"/a"}},
+			Err:     &ValidationError{Field: "DestFolder", Reason: "required"},
+		},
+		{
+			Request: FileStationCopyMoveStart{
+				Paths:      []string{"/a"},
+				DestFolder: "/b",
+				Overwrite:  "always",
+			},
+			Err: &ValidationError{Field: "Overwrite", Reason: "unknown mode"},
+		},
+		{
+			Request: FileStationExtractStart{DestFolder: "/b"},
+			Err:     &ValidationError{Field: "FilePath", Reason: "required"},
+		},
+		{
+			Request: FileStationExtractStart{FilePath: "/a.zip"},
+			Err:     &ValidationError{Field: "DestFolder", Reason: "required"},
+		},
+		{
+			Request: FileStationExtractList{},
+			Err:     &ValidationError{Field: "FilePath", Reason: "required"},
+		},
+		{
+			Request: FileStationCreateFolder{Names: []string{"a"}},
+			Err:     &ValidationError{Field: "FolderPaths", Reason: "required"},
+		},
+		{
+			Request: FileStationCreateFolder{
+				FolderPaths: []string{"/a", "/b"},
+				Names:       []string{"a"},
+			},
+			Err: &ValidationError{
+				Field:  "Names",
+				Reason: "must have one name for every folder path",
+			},
+		},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, validate(c.Request), c.Err)
+	}
+}
+
+func TestValidationErrorString(t *testing.T) {
+	err := &ValidationError{Field: "URI", Reason: "required"}
+	ensure.DeepEqual(t, err.Error(), "syno: invalid URI: required")
+}
+
+func TestCallValidates(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			t.Fatal("unexpected request")
+			return nil, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	err = c.Call(context.Background(), DownloadTaskCreate{}, nil)
+	ensure.DeepEqual(t, err, &ValidationError{Field: "URI", Reason: "required"})
+}
@@ -0,0 +1,122 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// DownloadTaskChange is a status change of a task observed by
+// WatchDownloadTasks. Previous is empty the first time the task is seen.
+type DownloadTaskChange struct {
+	Task     DownloadTask
+	Previous DownloadTaskStatus
+}
+
+// WatchDownloadTasks polls the tasks with the given IDs every interval, and
+// calls f for every change in their status, starting with their status when
+// first polled. It returns once every task is Done, or with the error from
+// polling, from f, or of the context.
+func (c *Client) WatchDownloadTasks(
+	ctx context.Context,
+	ids []string,
+	interval time.Duration,
+	f func(DownloadTaskChange) error,
+) error {
+	statuses := make(map[string]DownloadTaskStatus, len(ids))
+	pending := ids
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		var res DownloadTaskGetInfoResponse
+		err := c.Call(ctx, DownloadTaskGetInfo{IDs: pending}, &res)
+		if err != nil {
+			return err
+		}
+		pending = nil
+		for _, task := range res.Tasks {
+			if prev, seen := statuses[task.ID]; !seen || prev != task.Status {
+				statuses[task.ID] = task.Status
+				if err := f(DownloadTaskChange{Task: task, Previous: prev}); err != nil {
+					return err
+				}
+			}
+			if !task.Status.Done() {
+				pending = append(pending, task.ID)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// errNoCreatedTask is returned by CreateDownloadTaskAndWait if the created
+// task could not be found.
+var errNoCreatedTask = errors.New("syno: created download task not found")
+
+// downloadTaskIDs returns the IDs of all download tasks.
+func (c *Client) downloadTaskIDs(ctx context.Context) (map[string]bool, error) {
+	var res DownloadTaskListResponse
+	if err := c.Call(ctx, DownloadTaskList{}, &res); err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(res.Items))
+	for _, task := range res.Items {
+		ids[task.ID] = true
+	}
+	return ids, nil
+}
+
+// CreateDownloadTaskAndWait creates the download task, then waits for it and
+// any other tasks created from it to be Done as WatchDownloadTasks does, and
+// returns their final state. The created tasks are found by comparing the
+// tasks before and after creating them, so tasks created concurrently by
+// others may be included.
+func (c *Client) CreateDownloadTaskAndWait(
+	ctx context.Context,
+	r DownloadTaskCreate,
+	interval time.Duration,
+) ([]DownloadTask, error) {
+	before, err := c.downloadTaskIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Call(ctx, r, nil); err != nil {
+		return nil, err
+	}
+	after, err := c.downloadTaskIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for id := range after {
+		if !before[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errNoCreatedTask
+	}
+	sort.Strings(ids)
+
+	tasks := make(map[string]DownloadTask, len(ids))
+	err = c.WatchDownloadTasks(ctx, ids, interval, func(c DownloadTaskChange) error {
+		tasks[c.Task.ID] = c.Task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	final := make([]DownloadTask, 0, len(ids))
+	for _, id := range ids {
+		final = append(final, tasks[id])
+	}
+	return final, nil
+}
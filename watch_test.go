@@ -0,0 +1,142 @@
+package syno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func watchClient(t *testing.T, polls []string, ids *[]string) *Client {
+	var calls int
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Query().Get("method"), "getinfo")
+			*ids = append(*ids, r.URL.Query().Get("id"))
+			body := fmt.Sprintf(`{"success":true,"data":{"tasks":[%s]}}`, polls[calls])
+			calls++
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	return c
+}
+
+func TestWatchDownloadTasks(t *testing.T) {
+	var ids []string
+	c := watchClient(t, []string{
+		`{"id":"a","status":"waiting"},{"id":"b","status":"downloading"}`,
+		`{"id":"a","status":"downloading"},{"id":"b","status":"error"}`,
+		`{"id":"a","status":"downloading"}`,
+		`{"id":"a","status":"finished"}`,
+	}, &ids)
+	var changes []string
+	err := c.WatchDownloadTasks(
+		context.Background(),
+		[]string{"a", "b"},
+		time.Microsecond,
+		func(c DownloadTaskChange) error {
+			changes = append(changes, fmt.Sprintf("%s:%s->%s", c.Task.ID, c.Previous, c.Task.Status))
+			return nil
+		},
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, changes, []string{
+		"a:->waiting",
+		"b:->downloading",
+		"a:waiting->downloading",
+		"b:downloading->error",
+		"a:downloading->finished",
+	})
+	ensure.DeepEqual(t, ids, []string{"a,b", "a,b", "a", "a"})
+}
+
+func TestWatchDownloadTasksCallbackError(t *testing.T) {
+	var ids []string
+	c := watchClient(t, []string{`{"id":"a","status":"waiting"}`}, &ids)
+	givenErr := errors.New("")
+	err := c.WatchDownloadTasks(
+		context.Background(),
+		[]string{"a"},
+		time.Hour,
+		func(DownloadTaskChange) error { return givenErr },
+	)
+	ensure.DeepEqual(t, err, givenErr)
+}
+
+func TestWatchDownloadTasksContextDone(t *testing.T) {
+	var ids []string
+	c := watchClient(t, []string{`{"id":"a","status":"waiting"}`}, &ids)
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.WatchDownloadTasks(ctx, []string{"a"}, time.Hour, func(DownloadTaskChange) error {
+		cancel()
+		return nil
+	})
+	ensure.DeepEqual(t, err, context.Canceled)
+}
+
+func TestCreateDownloadTaskAndWait(t *testing.T) {
+	lists := []string{
+		`{"total":1,"offset":0,"tasks":[{"id":"a"}]}`,
+		`{"total":2,"offset":0,"tasks":[{"id":"a"},{"id":"b"}]}`,
+	}
+	var created bool
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.Nil(t, r.ParseForm())
+			data := "null"
+			switch r.Form.Get("method") {
+			case "list":
+				data, lists = lists[0], lists[1:]
+			case "create":
+				ensure.DeepEqual(t, r.Form.Get("uri"), "magnet:?a")
+				created = true
+			case "getinfo":
+				ensure.DeepEqual(t, r.Form.Get("id"), "b")
+				data = `{"tasks":[{"id":"b","status":"finished"}]}`
+			}
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":true,"data":` + data + `}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	tasks, err := c.CreateDownloadTaskAndWait(
+		context.Background(),
+		DownloadTaskCreate{URI: "magnet:?a"},
+		time.Microsecond,
+	)
+	ensure.Nil(t, err)
+	ensure.True(t, created)
+	ensure.DeepEqual(t, tasks, []DownloadTask{{ID: "b", Status: DownloadTaskFinished}})
+}
+
+func TestCreateDownloadTaskAndWaitNotFound(t *testing.T) {
+	c, err := NewClient(
+		ClientRawURL("http://foo.com/"),
+		ClientTransport(transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body: ioutil.NopCloser(strings.NewReader(
+					`{"success":true,"data":{"tasks":[{"id":"a"}]}}`)),
+			}, nil
+		})),
+	)
+	ensure.Nil(t, err)
+	_, err = c.CreateDownloadTaskAndWait(
+		context.Background(),
+		DownloadTaskCreate{URI: "magnet:?a"},
+		time.Microsecond,
+	)
+	ensure.DeepEqual(t, err, errNoCreatedTask)
+}